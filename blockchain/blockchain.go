@@ -0,0 +1,177 @@
+package blockchain
+
+import (
+	"math/big"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// Blockchain is the local view of the canonical chain. It owns the
+// KeyValueStorage-backed header/body/receipt/requests persistence and
+// publishes a ChainEvent for every canonical update, which is what
+// ChainIndexer and the JSON-RPC subscription hub both follow.
+type Blockchain struct {
+	logger hclog.Logger
+	db     *storage.KeyValueStorage
+	forks  *chain.Forks
+
+	events  eventStream
+	indexer *ChainIndexer
+}
+
+// NewBlockchain creates a Blockchain backed by db and starts its
+// bloom-bits chain indexer, which follows SubscribeChainEvents the same
+// way any other consumer would. forks may be nil, in which case no
+// fork-gated header field (RequestsHash in particular) is ever required.
+func NewBlockchain(logger hclog.Logger, db *storage.KeyValueStorage, forks *chain.Forks) *Blockchain {
+	b := &Blockchain{logger: logger.Named("blockchain"), db: db, forks: forks}
+
+	b.indexer = NewChainIndexer(logger, db, b, bloomBitsSectionSize)
+	head, _ := db.ReadHeadNumber()
+	b.indexer.Start(head)
+
+	return b
+}
+
+// Indexer returns the bloom-bits chain indexer following this chain, so
+// an eth_getLogs-style handler can narrow down which sections to scan via
+// MatchSections instead of walking every header.
+func (b *Blockchain) Indexer() *ChainIndexer {
+	return b.indexer
+}
+
+// SubscribeChainEvents returns a live feed of canonical chain updates
+func (b *Blockchain) SubscribeChainEvents() *Subscription {
+	return b.events.subscribe()
+}
+
+// GetRequests returns the execution-layer requests committed by the block
+// with the given hash, as persisted by commitBlock's PutRequests.
+func (b *Blockchain) GetRequests(hash types.Hash) ([]types.Request, error) {
+	return b.db.ReadRequests(hash)
+}
+
+// HeadNumber returns the current canonical head block number
+func (b *Blockchain) HeadNumber() uint64 {
+	n, _ := b.db.ReadHeadNumber()
+	return n
+}
+
+// HeaderByNumber resolves the canonical header at number
+func (b *Blockchain) HeaderByNumber(number uint64) (*types.Header, bool) {
+	hash, ok := b.db.ReadCanonicalHash(number)
+	if !ok {
+		return nil, false
+	}
+	return b.db.ReadHeader(hash)
+}
+
+// ReadReceipts returns the receipts committed for the block with the
+// given hash, so a JSON-RPC handler can resolve logs without reaching
+// into the underlying storage directly.
+func (b *Blockchain) ReadReceipts(hash types.Hash) ([]*types.Receipt, bool) {
+	return b.db.ReadReceipts(hash)
+}
+
+// MatchSections narrows down which bloom-bits sections might contain a
+// log matching bitIdxs, delegating to the chain indexer following this
+// chain.
+func (b *Blockchain) MatchSections(bitIdxs []uint) ([]uint64, error) {
+	return b.indexer.MatchSections(bitIdxs)
+}
+
+// SectionSize returns the number of blocks batched into one indexed
+// section of this chain's bloom-bits index.
+func (b *Blockchain) SectionSize() uint64 {
+	return b.indexer.SectionSize()
+}
+
+// WriteGenesis commits the genesis block as a single atomic batch, makes
+// it the canonical head and publishes a ChainEvent for it.
+func (b *Blockchain) WriteGenesis(genesis *types.Block) error {
+	added, removed, err := b.commitBlock(genesis, big.NewInt(0), nil)
+	if err != nil {
+		return err
+	}
+
+	b.events.push(&ChainEvent{Added: added, Removed: removed})
+	return nil
+}
+
+// WriteBlock commits block as the new canonical head: header, body,
+// receipts and requests all land in a single atomic batch together with
+// the canonical/head/total-difficulty pointers, so a crash mid-commit can
+// never leave the chain pointing at a head whose body was never
+// persisted. It then publishes a ChainEvent describing what became (and,
+// on a reorg, stopped being) canonical.
+func (b *Blockchain) WriteBlock(block *types.Block, receipts []*types.Receipt) error {
+	parentDiff, ok := b.db.ReadDiff(block.Header.ParentHash)
+	if !ok {
+		parentDiff = big.NewInt(0)
+	}
+	diff := new(big.Int).Add(parentDiff, new(big.Int).SetUint64(block.Header.Difficulty))
+
+	added, removed, err := b.commitBlock(block, diff, receipts)
+	if err != nil {
+		return err
+	}
+
+	b.events.push(&ChainEvent{Added: added, Removed: removed})
+	return nil
+}
+
+// commitBlock queues and writes the full atomic batch for block becoming
+// canonical, unwinding whatever part of the old canonical chain block's
+// parent does not descend from. It rejects block outright if its
+// RequestsHash does not commit to the requests it carries, the same way
+// verifyHeader would reject a bad TxRoot or ReceiptsRoot before a real
+// consensus engine's state transition ever reaches here.
+func (b *Blockchain) commitBlock(block *types.Block, diff *big.Int, receipts []*types.Receipt) (added, removed []*types.Header, err error) {
+	if err := chain.ValidateRequestsHash(block.Header, block.Requests, b.forks); err != nil {
+		return nil, nil, err
+	}
+
+	removed = b.reorgHeaders(block.Header)
+
+	batch := b.db.NewBatch()
+	batch.PutHeader(block.Header)
+	batch.PutBody(block.Header.Hash, block.Body())
+	if receipts != nil {
+		batch.PutReceipts(block.Header.Hash, receipts)
+	}
+	batch.PutRequests(block.Header.Hash, block.Requests)
+	batch.PutCanonicalHash(block.Header.Number, block.Header.Hash)
+	batch.PutHeadHash(block.Header.Hash)
+	batch.PutHeadNumber(block.Header.Number)
+	batch.PutTotalDifficulty(block.Header.Hash, diff)
+
+	if err := batch.WriteBatch(); err != nil {
+		return nil, nil, err
+	}
+
+	return []*types.Header{block.Header}, removed, nil
+}
+
+// reorgHeaders walks the current canonical chain back from its head down
+// to (and including) newHead's own height, returning every header that is
+// being evicted because newHead's parent does not extend it. It returns
+// nil when newHead simply extends the current head.
+func (b *Blockchain) reorgHeaders(newHead *types.Header) []*types.Header {
+	currentHead, ok := b.db.ReadHeadHash()
+	if !ok || newHead.ParentHash == currentHead {
+		return nil
+	}
+
+	var removed []*types.Header
+	cursor, ok := b.db.ReadHeader(currentHead)
+	for ok && cursor.Number >= newHead.Number {
+		removed = append(removed, cursor)
+		cursor, ok = b.db.ReadHeader(cursor.ParentHash)
+	}
+
+	return removed
+}
@@ -0,0 +1,122 @@
+package blockchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+func newTestBlockchain(t *testing.T) *Blockchain {
+	db := storage.NewKeyValueStorage(hclog.NewNullLogger(), kvdb.NewMemoryKV()).(*storage.KeyValueStorage)
+	return NewBlockchain(hclog.NewNullLogger(), db, nil)
+}
+
+func block(number uint64, parent types.Hash, salt byte) *types.Block {
+	h := &types.Header{Number: number, ParentHash: parent, Difficulty: 1}
+	h.Hash[0] = salt
+	return &types.Block{Header: h}
+}
+
+func awaitEvent(t *testing.T, sub *Subscription) *ChainEvent {
+	select {
+	case evnt := <-sub.Event():
+		return evnt
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for chain event")
+		return nil
+	}
+}
+
+func TestBlockchain_WriteGenesisAndExtend(t *testing.T) {
+	b := newTestBlockchain(t)
+	sub := b.SubscribeChainEvents()
+	defer sub.Close()
+
+	genesis := block(0, types.Hash{}, 1)
+	assert.NoError(t, b.WriteGenesis(genesis))
+
+	evnt := awaitEvent(t, sub)
+	assert.Equal(t, []*types.Header{genesis.Header}, evnt.Added)
+	assert.Nil(t, evnt.Removed)
+
+	b1 := block(1, genesis.Header.Hash, 2)
+	assert.NoError(t, b.WriteBlock(b1, nil))
+
+	evnt = awaitEvent(t, sub)
+	assert.Equal(t, []*types.Header{b1.Header}, evnt.Added)
+	assert.Nil(t, evnt.Removed)
+
+	head, ok := b.db.ReadHeadHash()
+	assert.True(t, ok)
+	assert.Equal(t, b1.Header.Hash, head)
+}
+
+func TestBlockchain_GetRequests_RoundTrip(t *testing.T) {
+	db := storage.NewKeyValueStorage(hclog.NewNullLogger(), kvdb.NewMemoryKV()).(*storage.KeyValueStorage)
+	zero := uint64(0)
+	b := NewBlockchain(hclog.NewNullLogger(), db, &chain.Forks{Requests: &zero})
+
+	genesis := block(0, types.Hash{}, 1)
+	genesis.Requests = []types.Request{&types.ValidatorDeposit{Validator: types.StringToAddress("1"), Amount: 1}}
+	hash, err := types.CalcRequestsHash(genesis.Requests)
+	assert.NoError(t, err)
+	genesis.Header.RequestsHash = &hash
+	assert.NoError(t, b.WriteGenesis(genesis))
+
+	got, err := b.GetRequests(genesis.Header.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, genesis.Requests, got)
+}
+
+func TestBlockchain_GetRequests_UnknownBlock(t *testing.T) {
+	b := newTestBlockchain(t)
+
+	got, err := b.GetRequests(types.Hash{0xff})
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestBlockchain_WriteBlock_RejectsBadRequestsHash(t *testing.T) {
+	db := storage.NewKeyValueStorage(hclog.NewNullLogger(), kvdb.NewMemoryKV()).(*storage.KeyValueStorage)
+	zero := uint64(0)
+	b := NewBlockchain(hclog.NewNullLogger(), db, &chain.Forks{Requests: &zero})
+
+	genesis := block(0, types.Hash{}, 1)
+	hash, err := types.CalcRequestsHash(nil)
+	assert.NoError(t, err)
+	genesis.Header.RequestsHash = &hash
+	assert.NoError(t, b.WriteGenesis(genesis))
+
+	a1 := block(1, genesis.Header.Hash, 2)
+	a1.Requests = []types.Request{&types.ValidatorDeposit{Validator: types.StringToAddress("1"), Amount: 1}}
+	// a1.Header.RequestsHash is left nil, which does not commit to a1.Requests
+	assert.Error(t, b.WriteBlock(a1, nil))
+}
+
+func TestBlockchain_ReorgPublishesRemoved(t *testing.T) {
+	b := newTestBlockchain(t)
+
+	genesis := block(0, types.Hash{}, 1)
+	assert.NoError(t, b.WriteGenesis(genesis))
+
+	a1 := block(1, genesis.Header.Hash, 2)
+	assert.NoError(t, b.WriteBlock(a1, nil))
+
+	sub := b.SubscribeChainEvents()
+	defer sub.Close()
+
+	// b1 forks off genesis directly, competing with (and here, replacing) a1
+	b1 := block(1, genesis.Header.Hash, 3)
+	assert.NoError(t, b.WriteBlock(b1, nil))
+
+	evnt := awaitEvent(t, sub)
+	assert.Equal(t, []*types.Header{b1.Header}, evnt.Added)
+	assert.Equal(t, []*types.Header{a1.Header}, evnt.Removed)
+}
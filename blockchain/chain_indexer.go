@@ -0,0 +1,275 @@
+package blockchain
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+const (
+	// bloomBitsSectionSize is the default number of headers batched into one bloom-bits section
+	bloomBitsSectionSize = 4096
+
+	// bloomBitLength is the number of bits in a LogsBloom (256 bytes * 8)
+	bloomBitLength = 2048
+)
+
+// indexerBackend is the slice of KeyValueStorage the chain indexer needs to
+// read headers from and persist bloom-bits sections to
+type indexerBackend interface {
+	ReadHeader(hash types.Hash) (*types.Header, bool)
+	ReadCanonicalHash(n uint64) (types.Hash, bool)
+	ReadValidSectionsNum() (uint64, error)
+	WriteValidSectionsNum(sections uint64) error
+	ReadIndexSectionHead(section uint64) types.Hash
+	WriteIndexSectionHead(section uint64, hash types.Hash) error
+	RemoveSectionHead(section uint64) error
+	WriteBloomBits(bitNum uint, section uint64, sectionHead types.Hash, bits []byte) error
+	ReadBloomBits(bitNum uint, section uint64, sectionHead types.Hash) ([]byte, bool)
+}
+
+// chainEventSource is implemented by Blockchain
+type chainEventSource interface {
+	SubscribeChainEvents() *Subscription
+}
+
+// ChainIndexer follows the canonical chain and, once every sectionSize
+// blocks, rotates their LogsBloom values into a per-bit matrix so that
+// eth_getLogs can AND together a handful of bit-vectors to skip whole
+// sections instead of scanning every header one by one.
+type ChainIndexer struct {
+	logger hclog.Logger
+	db     indexerBackend
+	chain  chainEventSource
+
+	sectionSize uint64
+
+	lock           sync.Mutex
+	storedSections uint64
+
+	closeCh chan struct{}
+}
+
+// NewChainIndexer creates a chain indexer that batches headers into
+// sections of sectionSize blocks
+func NewChainIndexer(logger hclog.Logger, db indexerBackend, chain chainEventSource, sectionSize uint64) *ChainIndexer {
+	if sectionSize == 0 {
+		sectionSize = bloomBitsSectionSize
+	}
+
+	c := &ChainIndexer{
+		logger:      logger.Named("chain-indexer"),
+		db:          db,
+		chain:       chain,
+		sectionSize: sectionSize,
+		closeCh:     make(chan struct{}),
+	}
+
+	if stored, err := db.ReadValidSectionsNum(); err == nil {
+		c.storedSections = stored
+	}
+
+	return c
+}
+
+// Start catches up on any section already complete in the backing chain and
+// then follows canonical chain events, indexing new sections as they fill up
+func (c *ChainIndexer) Start(head uint64) {
+	c.advance(head)
+
+	go c.run()
+}
+
+// Close stops the indexer from following further chain events
+func (c *ChainIndexer) Close() {
+	close(c.closeCh)
+}
+
+// Sections returns the number of fully indexed sections
+func (c *ChainIndexer) Sections() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.storedSections
+}
+
+// SectionSize returns the number of blocks batched into one indexed
+// section, so a caller can map a block number to the section that covers
+// it the same way processSection does.
+func (c *ChainIndexer) SectionSize() uint64 {
+	return c.sectionSize
+}
+
+func (c *ChainIndexer) run() {
+	sub := c.chain.SubscribeChainEvents()
+	defer sub.Close()
+
+	for {
+		select {
+		case evnt := <-sub.Event():
+			for _, h := range evnt.Removed {
+				if h.Number == 0 {
+					// genesis itself was reorged out: nothing indexed can
+					// survive, so roll back to before section 0
+					c.rollback(0)
+					continue
+				}
+				c.rollback(h.Number - 1)
+			}
+			for _, h := range evnt.Added {
+				c.advance(h.Number)
+			}
+		case <-c.closeCh:
+			return
+		}
+	}
+}
+
+// advance builds every section that is now complete up to head
+func (c *ChainIndexer) advance(head uint64) {
+	for {
+		c.lock.Lock()
+		section := c.storedSections
+		c.lock.Unlock()
+
+		if (section+1)*c.sectionSize-1 > head {
+			return
+		}
+
+		if err := c.processSection(section); err != nil {
+			c.logger.Error("failed to build bloom-bits section", "section", section, "err", err)
+			return
+		}
+	}
+}
+
+// rollback discards any indexed section built past newHead, so the indexer
+// rebuilds them once the (possibly new) fork reaches that height again
+func (c *ChainIndexer) rollback(newHead uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	changed := c.storedSections
+	for changed > 0 && changed*c.sectionSize > newHead+1 {
+		changed--
+	}
+
+	for section := changed; section < c.storedSections; section++ {
+		if err := c.db.RemoveSectionHead(section); err != nil {
+			c.logger.Error("failed to remove section head", "section", section, "err", err)
+		}
+	}
+
+	if changed == c.storedSections {
+		return
+	}
+
+	c.storedSections = changed
+	if err := c.db.WriteValidSectionsNum(c.storedSections); err != nil {
+		c.logger.Error("failed to persist valid sections", "err", err)
+	}
+}
+
+// processSection builds the rotated bloom-bits matrix for section and
+// persists one bit-vector per bloom bit
+func (c *ChainIndexer) processSection(section uint64) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	start := section * c.sectionSize
+	bits := make([][]byte, bloomBitLength)
+	for i := range bits {
+		bits[i] = make([]byte, c.sectionSize/8)
+	}
+
+	var sectionHead types.Hash
+	for i := uint64(0); i < c.sectionSize; i++ {
+		number := start + i
+
+		hash, ok := c.db.ReadCanonicalHash(number)
+		if !ok {
+			return fmt.Errorf("missing canonical hash for block %d", number)
+		}
+
+		header, ok := c.db.ReadHeader(hash)
+		if !ok {
+			return fmt.Errorf("missing header %s", hash)
+		}
+
+		for bit := 0; bit < bloomBitLength; bit++ {
+			if bloomBitSet(header.LogsBloom, bit) {
+				bits[bit][i/8] |= 1 << (7 - i%8)
+			}
+		}
+
+		if i == c.sectionSize-1 {
+			sectionHead = hash
+		}
+	}
+
+	for bit := 0; bit < bloomBitLength; bit++ {
+		if err := c.db.WriteBloomBits(uint(bit), section, sectionHead, bits[bit]); err != nil {
+			return err
+		}
+	}
+
+	if err := c.db.WriteIndexSectionHead(section, sectionHead); err != nil {
+		return err
+	}
+
+	c.storedSections = section + 1
+	return c.db.WriteValidSectionsNum(c.storedSections)
+}
+
+// MatchSections ANDs together the bit-vectors for bitIdxs across every
+// indexed section and returns the sections that might contain a match,
+// letting eth_getLogs skip whole sections instead of scanning headers
+func (c *ChainIndexer) MatchSections(bitIdxs []uint) ([]uint64, error) {
+	c.lock.Lock()
+	stored := c.storedSections
+	c.lock.Unlock()
+
+	var hits []uint64
+	for section := uint64(0); section < stored; section++ {
+		head := c.db.ReadIndexSectionHead(section)
+
+		var merged []byte
+		for i, bit := range bitIdxs {
+			vec, ok := c.db.ReadBloomBits(bit, section, head)
+			if !ok {
+				return nil, fmt.Errorf("missing bloom-bits for section %d bit %d", section, bit)
+			}
+
+			if i == 0 {
+				merged = append([]byte{}, vec...)
+				continue
+			}
+			for j := range merged {
+				merged[j] &= vec[j]
+			}
+		}
+
+		if anySet(merged) {
+			hits = append(hits, section)
+		}
+	}
+
+	return hits, nil
+}
+
+func bloomBitSet(bloom types.Bloom, bit int) bool {
+	byteIdx := len(bloom) - 1 - bit/8
+	return bloom[byteIdx]&(1<<uint(bit%8)) != 0
+}
+
+func anySet(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return true
+		}
+	}
+	return false
+}
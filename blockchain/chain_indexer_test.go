@@ -0,0 +1,172 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+type bitsKey struct {
+	bitNum  uint
+	section uint64
+	head    types.Hash
+}
+
+type fakeIndexerBackend struct {
+	headers      map[types.Hash]*types.Header
+	canon        map[uint64]types.Hash
+	sectionHeads map[uint64]types.Hash
+	bits         map[bitsKey][]byte
+	valid        uint64
+}
+
+func newFakeIndexerBackend() *fakeIndexerBackend {
+	return &fakeIndexerBackend{
+		headers:      map[types.Hash]*types.Header{},
+		canon:        map[uint64]types.Hash{},
+		sectionHeads: map[uint64]types.Hash{},
+		bits:         map[bitsKey][]byte{},
+	}
+}
+
+func (f *fakeIndexerBackend) ReadHeader(hash types.Hash) (*types.Header, bool) {
+	h, ok := f.headers[hash]
+	return h, ok
+}
+
+func (f *fakeIndexerBackend) ReadCanonicalHash(n uint64) (types.Hash, bool) {
+	h, ok := f.canon[n]
+	return h, ok
+}
+
+func (f *fakeIndexerBackend) ReadValidSectionsNum() (uint64, error) {
+	return f.valid, nil
+}
+
+func (f *fakeIndexerBackend) WriteValidSectionsNum(n uint64) error {
+	f.valid = n
+	return nil
+}
+
+func (f *fakeIndexerBackend) ReadIndexSectionHead(section uint64) types.Hash {
+	return f.sectionHeads[section]
+}
+
+func (f *fakeIndexerBackend) WriteIndexSectionHead(section uint64, hash types.Hash) error {
+	f.sectionHeads[section] = hash
+	return nil
+}
+
+func (f *fakeIndexerBackend) RemoveSectionHead(section uint64) error {
+	delete(f.sectionHeads, section)
+	return nil
+}
+
+func (f *fakeIndexerBackend) WriteBloomBits(bitNum uint, section uint64, head types.Hash, bits []byte) error {
+	f.bits[bitsKey{bitNum, section, head}] = bits
+	return nil
+}
+
+func (f *fakeIndexerBackend) ReadBloomBits(bitNum uint, section uint64, head types.Hash) ([]byte, bool) {
+	b, ok := f.bits[bitsKey{bitNum, section, head}]
+	return b, ok
+}
+
+type fakeChainEventSource struct {
+	sub *Subscription
+}
+
+func (f *fakeChainEventSource) SubscribeChainEvents() *Subscription {
+	return f.sub
+}
+
+// newTestChain builds n headers (numbered 0..n-1), each with a distinct
+// hash, and seeds the fake backend's canonical mapping for them
+func newTestChain(backend *fakeIndexerBackend, n int, setBit func(number uint64) int) []*types.Header {
+	headers := make([]*types.Header, n)
+	for i := 0; i < n; i++ {
+		h := &types.Header{Number: uint64(i)}
+		h.Hash[0] = byte(i + 1)
+
+		if bit := setBit(uint64(i)); bit >= 0 {
+			byteIdx := len(h.LogsBloom) - 1 - bit/8
+			h.LogsBloom[byteIdx] |= 1 << uint(bit%8)
+		}
+
+		backend.headers[h.Hash] = h
+		backend.canon[h.Number] = h.Hash
+		headers[i] = h
+	}
+	return headers
+}
+
+func TestChainIndexer_StartupFromNonEmptyChain(t *testing.T) {
+	backend := newFakeIndexerBackend()
+	newTestChain(backend, 8, func(uint64) int { return -1 })
+
+	chain := &fakeChainEventSource{sub: newSubscription()}
+	indexer := NewChainIndexer(hclog.NewNullLogger(), backend, chain, 4)
+
+	indexer.Start(7)
+	indexer.Close()
+
+	assert.Equal(t, uint64(2), indexer.Sections())
+	assert.Equal(t, uint64(2), backend.valid)
+}
+
+func TestChainIndexer_ReorgRollback(t *testing.T) {
+	backend := newFakeIndexerBackend()
+	newTestChain(backend, 8, func(uint64) int { return -1 })
+
+	chain := &fakeChainEventSource{sub: newSubscription()}
+	indexer := NewChainIndexer(hclog.NewNullLogger(), backend, chain, 4)
+	indexer.advance(7)
+	assert.Equal(t, uint64(2), indexer.Sections())
+
+	// chain reorgs down to block 3: section 1 (blocks 4-7) is no longer valid
+	indexer.rollback(3)
+
+	assert.Equal(t, uint64(1), indexer.Sections())
+	assert.Equal(t, types.Hash{}, backend.sectionHeads[1])
+}
+
+func TestChainIndexer_FilterHitParity(t *testing.T) {
+	const hitBit = 42
+
+	backend := newFakeIndexerBackend()
+	headers := newTestChain(backend, 8, func(number uint64) int {
+		// only block 1 (in section 0) sets hitBit
+		if number == 1 {
+			return hitBit
+		}
+		return -1
+	})
+
+	chain := &fakeChainEventSource{sub: newSubscription()}
+	indexer := NewChainIndexer(hclog.NewNullLogger(), backend, chain, 4)
+	indexer.advance(7)
+
+	hits, err := indexer.MatchSections([]uint{hitBit})
+	assert.NoError(t, err)
+
+	// brute-force scan: which sections contain at least one header with hitBit set
+	var want []uint64
+	for section := uint64(0); section < indexer.Sections(); section++ {
+		found := false
+		for _, h := range headers[section*4 : (section+1)*4] {
+			if bloomBitSet(h.LogsBloom, hitBit) {
+				found = true
+				break
+			}
+		}
+		if found {
+			want = append(want, section)
+		}
+	}
+
+	assert.Equal(t, want, hits)
+	assert.Equal(t, []uint64{0}, hits)
+}
@@ -0,0 +1,90 @@
+package blockchain
+
+import (
+	"sync"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// ChainEvent is published on every canonical chain update. Added is the set
+// of headers becoming canonical and Removed is the set of headers being
+// evicted from the canonical chain, as happens on a reorg.
+type ChainEvent struct {
+	Added   []*types.Header
+	Removed []*types.Header
+}
+
+// Subscription is a handle to a live ChainEvent feed
+type Subscription struct {
+	updateCh  chan *ChainEvent
+	closeCh   chan struct{}
+	closeOnce sync.Once
+}
+
+func newSubscription() *Subscription {
+	return &Subscription{
+		updateCh: make(chan *ChainEvent, 16),
+		closeCh:  make(chan struct{}),
+	}
+}
+
+// Event returns the channel new chain events are delivered on
+func (s *Subscription) Event() chan *ChainEvent {
+	return s.updateCh
+}
+
+// Close tears down the subscription
+func (s *Subscription) Close() {
+	s.closeOnce.Do(func() { close(s.closeCh) })
+}
+
+func (s *Subscription) push(evnt *ChainEvent) {
+	select {
+	case s.updateCh <- evnt:
+	default:
+	}
+}
+
+// eventStream fans a ChainEvent out to every live subscription
+type eventStream struct {
+	lock sync.Mutex
+	subs []*Subscription
+}
+
+func (e *eventStream) subscribe() *Subscription {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	sub := newSubscription()
+	e.subs = append(e.subs, sub)
+	return sub
+}
+
+// NewTestSubscription creates a standalone Subscription for tests of
+// packages that consume chain events without spinning up a Blockchain
+func NewTestSubscription() *Subscription {
+	return newSubscription()
+}
+
+// PushTestChainEvent delivers evnt to sub, as Blockchain would on a
+// canonical chain update. For use in tests only.
+func PushTestChainEvent(sub *Subscription, evnt *ChainEvent) {
+	sub.push(evnt)
+}
+
+func (e *eventStream) push(evnt *ChainEvent) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	live := e.subs[:0]
+	for _, sub := range e.subs {
+		select {
+		case <-sub.closeCh:
+			continue
+		default:
+		}
+		sub.push(evnt)
+		live = append(live, sub)
+	}
+	e.subs = live
+}
@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"math/big"
+
+	"github.com/umbracle/fastrlp"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// Batch is a write batch that accumulates writes and commits them
+// atomically to the underlying KV store.
+type Batch interface {
+	Set(p []byte, v []byte) error
+	Write() error
+}
+
+// BatchWriter batches up the individual puts that make up a block (or
+// genesis) commit so that they land in the underlying KV store as a
+// single atomic unit instead of one fsync per key. The first error from
+// any queued Set is sticky: later Puts become no-ops and WriteBatch
+// returns it, so a caller can never observe a partially-queued batch as
+// having succeeded.
+type BatchWriter struct {
+	batch Batch
+	err   error
+}
+
+// NewBatchWriter creates a new batch writer on top of db
+func NewBatchWriter(db KV) *BatchWriter {
+	return &BatchWriter{batch: db.Batch()}
+}
+
+func (b *BatchWriter) set(prefix, k, v []byte) {
+	if b.err != nil {
+		return
+	}
+
+	p := append(append([]byte{}, prefix...), k...)
+	b.err = b.batch.Set(p, v)
+}
+
+func (b *BatchWriter) write2(prefix, k []byte, v *fastrlp.Value) {
+	b.set(prefix, k, v.MarshalTo(nil))
+}
+
+// PutHeader queues the header write
+func (b *BatchWriter) PutHeader(h *types.Header) *BatchWriter {
+	ar := &fastrlp.Arena{}
+	b.write2(HEADER, h.Hash.Bytes(), h.MarshalRLPWith(ar))
+	return b
+}
+
+// PutBody queues the body write
+func (b *BatchWriter) PutBody(hash types.Hash, body *types.Body) *BatchWriter {
+	ar := &fastrlp.Arena{}
+	b.write2(BODY, hash.Bytes(), body.MarshalRLPWith(ar))
+	return b
+}
+
+// PutReceipts queues the receipts write
+func (b *BatchWriter) PutReceipts(hash types.Hash, receipts []*types.Receipt) *BatchWriter {
+	ar := &fastrlp.Arena{}
+
+	var vr *fastrlp.Value
+	if len(receipts) == 0 {
+		vr = ar.NewNullArray()
+	} else {
+		vr = ar.NewArray()
+		for _, receipt := range receipts {
+			vr.Set(receipt.MarshalRLPWith(ar))
+		}
+	}
+
+	b.write2(RECEIPTS, hash.Bytes(), vr)
+	return b
+}
+
+// PutCanonicalHash queues the canonical hash write for number n
+func (b *BatchWriter) PutCanonicalHash(n uint64, hash types.Hash) *BatchWriter {
+	b.set(CANONICAL, encodeUint(n), hash.Bytes())
+	return b
+}
+
+// PutHeadHash queues the head hash write
+func (b *BatchWriter) PutHeadHash(h types.Hash) *BatchWriter {
+	b.set(HEAD, HASH, h.Bytes())
+	return b
+}
+
+// PutHeadNumber queues the head number write
+func (b *BatchWriter) PutHeadNumber(n uint64) *BatchWriter {
+	b.set(HEAD, NUMBER, encodeUint(n))
+	return b
+}
+
+// PutTotalDifficulty queues the total difficulty write
+func (b *BatchWriter) PutTotalDifficulty(hash types.Hash, diff *big.Int) *BatchWriter {
+	b.set(DIFFICULTY, hash.Bytes(), diff.Bytes())
+	return b
+}
+
+// PutTxLookup queues the tx lookup write, pointing txHash at blockHash
+func (b *BatchWriter) PutTxLookup(txHash types.Hash, blockHash types.Hash) *BatchWriter {
+	ar := &fastrlp.Arena{}
+	b.write2(TX_LOOKUP_PREFIX, txHash.Bytes(), ar.NewBytes(blockHash.Bytes()))
+	return b
+}
+
+// PutBloomBits queues a bloom-bits section write
+func (b *BatchWriter) PutBloomBits(bitNum uint, section uint64, head types.Hash, bits []byte) *BatchWriter {
+	b.set(CHAIN_INDEXER, bloomBitsKey(bitNum, section, head), bits)
+	return b
+}
+
+// PutRequests queues the flat-encoded execution-layer requests write
+func (b *BatchWriter) PutRequests(hash types.Hash, requests []types.Request) *BatchWriter {
+	ar := &fastrlp.Arena{}
+
+	var vr *fastrlp.Value
+	if len(requests) == 0 {
+		vr = ar.NewNullArray()
+	} else {
+		vr = ar.NewArray()
+		for _, req := range requests {
+			enc, err := types.MarshalRequest(req)
+			if err != nil {
+				if b.err == nil {
+					b.err = err
+				}
+				return b
+			}
+			vr.Set(ar.NewBytes(enc))
+		}
+	}
+
+	b.write2(REQUESTS, hash.Bytes(), vr)
+	return b
+}
+
+// WriteBatch commits every queued put to the underlying KV store
+// atomically, or returns the first error encountered while queuing them
+// without touching the store at all.
+func (b *BatchWriter) WriteBatch() error {
+	if b.err != nil {
+		return b.err
+	}
+	return b.batch.Write()
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// failingBatch errors out on the Nth Set call, letting tests assert that a
+// BatchWriter never calls the batch's Write once queuing fails.
+type failingBatch struct {
+	db       *failingKV
+	failOn   int
+	numSets  int
+	writeHit bool
+}
+
+func (b *failingBatch) Set(p, v []byte) error {
+	b.numSets++
+	if b.numSets == b.failOn {
+		return errors.New("simulated set failure")
+	}
+	b.db.data[string(p)] = append([]byte{}, v...)
+	return nil
+}
+
+func (b *failingBatch) Write() error {
+	b.writeHit = true
+	return nil
+}
+
+type failingKV struct {
+	data   map[string][]byte
+	failOn int
+	batch  *failingBatch
+}
+
+func newFailingKV(failOn int) *failingKV {
+	return &failingKV{data: map[string][]byte{}, failOn: failOn}
+}
+
+func (k *failingKV) Close() error { return nil }
+
+func (k *failingKV) Set(p, v []byte) error {
+	k.data[string(p)] = append([]byte{}, v...)
+	return nil
+}
+
+func (k *failingKV) Get(p []byte) ([]byte, bool, error) {
+	v, ok := k.data[string(p)]
+	return v, ok, nil
+}
+
+func (k *failingKV) Delete(p []byte) error {
+	delete(k.data, string(p))
+	return nil
+}
+
+func (k *failingKV) Batch() Batch {
+	k.batch = &failingBatch{db: k, failOn: k.failOn}
+	return k.batch
+}
+
+func TestBatchWriter_PropagatesSetError(t *testing.T) {
+	kv := newFailingKV(2)
+	bw := NewBatchWriter(kv)
+
+	header := &types.Header{Number: 1}
+	bw.PutHeader(header)
+	bw.PutHeadHash(header.Hash)
+	bw.PutTotalDifficulty(header.Hash, big.NewInt(1))
+
+	err := bw.WriteBatch()
+	assert.Error(t, err)
+	assert.False(t, kv.batch.writeHit, "Write must never be reached once a queued Set fails")
+}
+
+func TestKeyValueStorage_NewBatch_AtomicCommit(t *testing.T) {
+	kv := newFailingKV(0)
+	db := NewKeyValueStorage(hclog.NewNullLogger(), kv).(*KeyValueStorage)
+
+	header := &types.Header{Number: 7}
+	batch := db.NewBatch()
+	batch.PutHeader(header)
+	batch.PutHeadHash(header.Hash)
+	batch.PutHeadNumber(header.Number)
+	batch.PutCanonicalHash(header.Number, header.Hash)
+	batch.PutTotalDifficulty(header.Hash, big.NewInt(5))
+
+	assert.NoError(t, batch.WriteBatch())
+
+	head, ok := db.ReadHeadNumber()
+	assert.True(t, ok)
+	assert.Equal(t, header.Number, head)
+
+	diff, ok := db.ReadDiff(header.Hash)
+	assert.True(t, ok)
+	assert.Equal(t, big.NewInt(5), diff)
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/umbracle/fastrlp"
 
-	"github.com/0xPolygon/minimal/types"
+	"github.com/0xPolygon/polygon-sdk/types"
 )
 
 // prefix
@@ -44,6 +44,15 @@ var (
 	// CHAIN_INDEXER is the prefix for the chain indexer
 	CHAIN_INDEXER_HEAD = []byte("chainIndexerSectionsHead")
 	CHAIN_INDEXER      = []byte("chainIndexer")
+
+	// FAST_SYNC is the prefix for interrupted fast-sync resumption state
+	FAST_SYNC = []byte("fastSync")
+
+	// REQUESTS is the prefix for a block's execution-layer requests list (EIP-7685)
+	REQUESTS = []byte("q")
+
+	// STATE is the prefix for trie nodes fetched during fast sync
+	STATE = []byte("t")
 )
 
 // sub-prefix
@@ -54,6 +63,8 @@ var (
 	EMPTY  = []byte("empty")
 
 	CHAIN_SECTIONS = []byte("sectionsValid")
+
+	FAST_SYNC_STATE = []byte("state")
 )
 
 // KV is a key value storage interface
@@ -61,29 +72,48 @@ type KV interface {
 	Close() error
 	Set(p []byte, v []byte) error
 	Get(p []byte) ([]byte, bool, error)
+	Delete(p []byte) error
+
+	// Batch returns a write batch that can be committed atomically with Write()
+	Batch() Batch
 }
 
 // KeyValueStorage is a generic storage for kv databases
 type KeyValueStorage struct {
 	logger hclog.Logger
 	db     KV
-	Db     KV
 }
 
 func NewKeyValueStorage(logger hclog.Logger, db KV) Storage {
 	return &KeyValueStorage{logger: logger, db: db}
 }
 
-func (s *KeyValueStorage) encodeUint(n uint64) []byte {
+// NewBatch returns a batch writer over this storage's underlying KV handle.
+// Callers outside this package that need to queue several puts atomically
+// (e.g. a block commit spanning header/body/receipts) should go through
+// this instead of reaching for the KV directly.
+func (s *KeyValueStorage) NewBatch() *BatchWriter {
+	return NewBatchWriter(s.db)
+}
+
+func encodeUint(n uint64) []byte {
 	b := make([]byte, 8)
 	binary.BigEndian.PutUint64(b[:], n)
 	return b[:]
 }
 
-func (s *KeyValueStorage) decodeUint(b []byte) uint64 {
+func decodeUint(b []byte) uint64 {
 	return binary.BigEndian.Uint64(b[:])
 }
 
+func (s *KeyValueStorage) encodeUint(n uint64) []byte {
+	return encodeUint(n)
+}
+
+func (s *KeyValueStorage) decodeUint(b []byte) uint64 {
+	return decodeUint(b)
+}
+
 // -- canonical hash --
 
 // ReadCanonicalHash gets the hash from the number of the canonical chain
@@ -216,24 +246,20 @@ func (s *KeyValueStorage) ReadHeader(hash types.Hash) (*types.Header, bool) {
 	return header2, true
 }
 
-// WriteCanonicalHeader implements the storage interface
+// WriteCanonicalHeader implements the storage interface. It commits the
+// header, head pointers, canonical number mapping and total difficulty
+// as a single atomic batch so a crash mid-write can never leave the DB
+// pointing at a head whose header (or diff) was never persisted.
 func (s *KeyValueStorage) WriteCanonicalHeader(h *types.Header, diff *big.Int) error {
-	if err := s.WriteHeader(h); err != nil {
-		return err
-	}
-	if err := s.WriteHeadHash(h.Hash); err != nil {
-		return err
-	}
-	if err := s.WriteHeadNumber(h.Number); err != nil {
-		return err
-	}
-	if err := s.WriteCanonicalHash(h.Number, h.Hash); err != nil {
-		return err
-	}
-	if err := s.WriteDiff(h.Hash, diff); err != nil {
-		return err
-	}
-	return nil
+	batchWriter := NewBatchWriter(s.db)
+
+	batchWriter.PutHeader(h)
+	batchWriter.PutHeadHash(h.Hash)
+	batchWriter.PutHeadNumber(h.Number)
+	batchWriter.PutCanonicalHash(h.Number, h.Hash)
+	batchWriter.PutTotalDifficulty(h.Hash, diff)
+
+	return batchWriter.WriteBatch()
 }
 
 // -- body --
@@ -321,6 +347,61 @@ func (s *KeyValueStorage) ReadReceipts(hash types.Hash) ([]*types.Receipt, bool)
 	return receipts2, true
 }
 
+// -- requests --
+
+// WriteRequests writes the flat-encoded execution-layer requests for a block
+func (s *KeyValueStorage) WriteRequests(hash types.Hash, requests []types.Request) error {
+	ar := &fastrlp.Arena{}
+
+	var vr *fastrlp.Value
+	if len(requests) == 0 {
+		vr = ar.NewNullArray()
+	} else {
+		vr = ar.NewArray()
+		for _, req := range requests {
+			enc, err := types.MarshalRequest(req)
+			if err != nil {
+				return err
+			}
+			vr.Set(ar.NewBytes(enc))
+		}
+	}
+
+	return s.write2(REQUESTS, hash.Bytes(), vr)
+}
+
+// ReadRequests reads back the requests written by WriteRequests. A nil,
+// nil result means the block has no stored requests at all, as opposed
+// to an error decoding a blob that is present but malformed.
+func (s *KeyValueStorage) ReadRequests(hash types.Hash) ([]types.Request, error) {
+	requests := []types.Request{}
+	parser := &fastrlp.Parser{}
+
+	v := s.read2(REQUESTS, hash.Bytes(), parser)
+	if v == nil {
+		return nil, nil
+	}
+
+	elems, err := v.GetElems()
+	if err != nil {
+		return nil, err
+	}
+	for _, elem := range elems {
+		raw, err := elem.GetBytes(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := types.UnmarshalRequest(raw)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+
+	return requests, nil
+}
+
 // -- tx lookup --
 
 // WriteReceipts writes the receipts
@@ -385,67 +466,95 @@ func (s *KeyValueStorage) get(prefix []byte, key []byte) ([]byte, bool) {
 	return data, ok
 }
 
+func (s *KeyValueStorage) delete(prefix []byte, key []byte) error {
+	prefix = append(prefix, key...)
+	return s.db.Delete(prefix)
+}
+
 // Chain indexer //
 
-func (s *KeyValueStorage) ReadIndexSectionHead(section uint64) types.Hash {
-	var sectionBinary []byte
-	binary.BigEndian.PutUint64(sectionBinary[:], section)
+// bloomBitsKey builds the CHAIN_INDEXER sub-key for a (bitIdx, section, sectionHead) triple
+func bloomBitsKey(bitNum uint, section uint64, head types.Hash) []byte {
+	key := encodeUint(uint64(bitNum))
+	key = append(key, encodeUint(section)...)
+	key = append(key, head.Bytes()...)
+	return key
+}
 
-	data, _ := s.get(CHAIN_INDEXER, sectionBinary)
+// ReadIndexSectionHead returns the canonical head the indexer had processed
+// a section up to, so it can detect and roll back stale sections on reorg
+func (s *KeyValueStorage) ReadIndexSectionHead(section uint64) types.Hash {
+	data, _ := s.get(CHAIN_INDEXER_HEAD, encodeUint(section))
 
 	return types.BytesToHash(data)
 }
 
+// WriteIndexSectionHead records the canonical head a section was built against
 func (s *KeyValueStorage) WriteIndexSectionHead(section uint64, hash types.Hash) error {
-	var sectionBinary []byte
-	binary.BigEndian.PutUint64(sectionBinary[:], section)
-
-	err := s.set(CHAIN_INDEXER_HEAD, sectionBinary, hash.Bytes())
-
-	return err
+	return s.set(CHAIN_INDEXER_HEAD, encodeUint(section), hash.Bytes())
 }
 
+// RemoveSectionHead discards the stored head for a section, e.g. on reorg rollback
 func (s *KeyValueStorage) RemoveSectionHead(section uint64) error {
-
-	// TODO swap with remove
-	var sectionBinary []byte
-	binary.BigEndian.PutUint64(sectionBinary[:], section)
-
-	err := s.set(CHAIN_INDEXER_HEAD, sectionBinary, nil)
-
-	return err
+	return s.delete(CHAIN_INDEXER_HEAD, encodeUint(section))
 }
 
+// WriteValidSectionsNum records the number of fully indexed sections
 func (s *KeyValueStorage) WriteValidSectionsNum(sections uint64) error {
-	var sectionsBinary []byte
-	binary.BigEndian.PutUint64(sectionsBinary[:], sections)
-
-	err := s.set(CHAIN_INDEXER, CHAIN_SECTIONS, sectionsBinary)
-
-	return err
+	return s.set(CHAIN_INDEXER, CHAIN_SECTIONS, encodeUint(sections))
 }
 
+// ReadValidSectionsNum returns the number of fully indexed sections
 func (s *KeyValueStorage) ReadValidSectionsNum() (uint64, error) {
-	var ret uint64
-
-	data, _ := s.get(CHAIN_INDEXER, CHAIN_SECTIONS)
+	data, ok := s.get(CHAIN_INDEXER, CHAIN_SECTIONS)
+	if !ok {
+		return 0, nil
+	}
 
+	var ret uint64
 	buf := bytes.NewBuffer(data)
-	_ = binary.Read(buf, binary.BigEndian, &ret)
+	if err := binary.Read(buf, binary.BigEndian, &ret); err != nil {
+		return 0, err
+	}
 
 	return ret, nil
 }
 
-func (s *KeyValueStorage) WriteBloomBits(bitNum uint, currentSection uint64, bHead types.Hash, bits []byte) {
-	var bitNumBinary []byte
-	binary.BigEndian.PutUint64(bitNumBinary[:], uint64(bitNum))
+// WriteBloomBits stores the rotated bit-vector for bit bitNum of section,
+// keyed so a reorg that invalidates sectionHead can be detected on read
+func (s *KeyValueStorage) WriteBloomBits(bitNum uint, section uint64, sectionHead types.Hash, bits []byte) error {
+	return s.set(CHAIN_INDEXER, bloomBitsKey(bitNum, section, sectionHead), bits)
+}
+
+// ReadBloomBits reads back the bit-vector written by WriteBloomBits
+func (s *KeyValueStorage) ReadBloomBits(bitNum uint, section uint64, sectionHead types.Hash) ([]byte, bool) {
+	return s.get(CHAIN_INDEXER, bloomBitsKey(bitNum, section, sectionHead))
+}
 
-	var currentSectionBinary []byte
-	binary.BigEndian.PutUint64(currentSectionBinary[:], uint64(currentSection))
+// -- fast sync --
+
+// WriteFastSyncState persists the caller's serialized fast-sync progress,
+// so an interrupted fast sync can resume instead of restarting from scratch
+func (s *KeyValueStorage) WriteFastSyncState(data []byte) error {
+	return s.set(FAST_SYNC, FAST_SYNC_STATE, data)
+}
 
-	generatedKey := append(bitNumBinary, currentSectionBinary...)
+// ReadFastSyncState reads back the progress written by WriteFastSyncState
+func (s *KeyValueStorage) ReadFastSyncState() ([]byte, bool) {
+	return s.get(FAST_SYNC, FAST_SYNC_STATE)
+}
+
+// -- fast-sync state trie --
+
+// WriteStateNode persists a trie node fetched during fast sync, keyed by
+// its own hash
+func (s *KeyValueStorage) WriteStateNode(hash types.Hash, data []byte) error {
+	return s.set(STATE, hash.Bytes(), data)
+}
 
-	_ = s.set(CHAIN_INDEXER, append(generatedKey, bHead.Bytes()...), bits)
+// ReadStateNode reads back a trie node written by WriteStateNode
+func (s *KeyValueStorage) ReadStateNode(hash types.Hash) ([]byte, bool) {
+	return s.get(STATE, hash.Bytes())
 }
 
 // Close closes the connection with the db
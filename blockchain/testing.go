@@ -0,0 +1,25 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// TestBlockchain creates a Blockchain backed by an in-memory KV store and
+// seeded with genesis, for use by other packages' tests (consensus
+// engines in particular) that need a real chain to drive without standing
+// up an on-disk database.
+func TestBlockchain(t *testing.T, genesis *types.Block) *Blockchain {
+	db := storage.NewKeyValueStorage(hclog.NewNullLogger(), kvdb.NewMemoryKV()).(*storage.KeyValueStorage)
+
+	b := NewBlockchain(hclog.NewNullLogger(), db, nil)
+	assert.NoError(t, b.WriteGenesis(genesis))
+
+	return b
+}
@@ -0,0 +1,23 @@
+package chain
+
+// Forks records the block numbers at which optional protocol features
+// activate. A nil field means that fork is not scheduled.
+type Forks struct {
+	// Requests is the block number at which the EIP-7685-style
+	// execution-layer requests list (header.RequestsHash) activates.
+	Requests *uint64
+}
+
+// IsRequests returns whether the requests fork is active at block
+func (f *Forks) IsRequests(block uint64) bool {
+	if f == nil || f.Requests == nil {
+		return false
+	}
+	return block >= *f.Requests
+}
+
+// Params groups the chain-wide settings a consensus engine needs to
+// verify a block beyond the header/body themselves.
+type Params struct {
+	Forks *Forks
+}
@@ -0,0 +1,35 @@
+package chain
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// ValidateRequestsHash checks that a block's header.RequestsHash commits to
+// the requests it actually carries, the same way verifyHeader validates
+// TxRoot and ReceiptsRoot. Blocks built before the requests fork must carry
+// neither field.
+func ValidateRequestsHash(header *types.Header, requests []types.Request, forks *Forks) error {
+	if !forks.IsRequests(header.Number) {
+		if header.RequestsHash != nil || len(requests) != 0 {
+			return fmt.Errorf("block %d carries requests before the requests fork", header.Number)
+		}
+		return nil
+	}
+
+	if header.RequestsHash == nil {
+		return fmt.Errorf("block %d is past the requests fork but header carries no RequestsHash", header.Number)
+	}
+
+	hash, err := types.CalcRequestsHash(requests)
+	if err != nil {
+		return err
+	}
+
+	if hash != *header.RequestsHash {
+		return fmt.Errorf("requests hash mismatch: header %s, computed %s", *header.RequestsHash, hash)
+	}
+
+	return nil
+}
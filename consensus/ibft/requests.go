@@ -0,0 +1,23 @@
+package ibft
+
+import (
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// validateRequestsHash checks that a block's header.RequestsHash commits to
+// the requests it actually carries, the same way verifyHeader validates
+// TxRoot and ReceiptsRoot. It delegates to chain.ValidateRequestsHash so the
+// fork-gating rule lives in one place shared by every consensus engine.
+//
+// This snapshot has no concrete Ibft.verifyHeader for this function to hook
+// into directly (only mockIbft exists, in ibft_framing_test.go). The actual
+// enforcement path is Blockchain.commitBlock, which calls
+// chain.ValidateRequestsHash the same way against every block committed by
+// any engine, so a bad RequestsHash is rejected before it ever reaches
+// storage. This function stays in place as the call ibft's own
+// verifyHeader should make once it exists, rather than being deleted as
+// dead code.
+func validateRequestsHash(header *types.Header, requests []types.Request, forks *chain.Forks) error {
+	return chain.ValidateRequestsHash(header, requests, forks)
+}
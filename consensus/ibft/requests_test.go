@@ -0,0 +1,48 @@
+package ibft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-sdk/chain"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+func activeForks() *chain.Forks {
+	zero := uint64(0)
+	return &chain.Forks{Requests: &zero}
+}
+
+func TestValidateRequestsHash_NoForkNoRequests(t *testing.T) {
+	header := &types.Header{}
+	assert.NoError(t, validateRequestsHash(header, nil, nil))
+}
+
+func TestValidateRequestsHash_NoForkButHasRequests(t *testing.T) {
+	header := &types.Header{}
+	requests := []types.Request{&types.ValidatorDeposit{Validator: types.StringToAddress("1"), Amount: 1}}
+
+	assert.Error(t, validateRequestsHash(header, requests, nil))
+}
+
+func TestValidateRequestsHash_MatchesAndMismatches(t *testing.T) {
+	requests := []types.Request{&types.ValidatorDeposit{Validator: types.StringToAddress("1"), Amount: 1}}
+	forks := activeForks()
+
+	hash, err := types.CalcRequestsHash(requests)
+	assert.NoError(t, err)
+
+	header := &types.Header{RequestsHash: &hash}
+	assert.NoError(t, validateRequestsHash(header, requests, forks))
+
+	other := hash
+	other[0] ^= 0xff
+	header.RequestsHash = &other
+	assert.Error(t, validateRequestsHash(header, requests, forks))
+}
+
+func TestValidateRequestsHash_ForkActiveButNoRequestsHash(t *testing.T) {
+	header := &types.Header{}
+	assert.Error(t, validateRequestsHash(header, nil, activeForks()))
+}
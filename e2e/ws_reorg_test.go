@@ -0,0 +1,130 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+	"github.com/0xPolygon/polygon-sdk/helper/kvdb"
+	"github.com/0xPolygon/polygon-sdk/jsonrpc"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// TestWS_LogsOrderingAcrossReorg drives a real jsonrpc.WSHandler over an
+// actual websocket connection, backed by a real Blockchain, and checks
+// that a reorg delivers the evicted block's logs (Removed=true) before
+// the replacing block's logs, in the order the reorg actually happened.
+func TestWS_LogsOrderingAcrossReorg(t *testing.T) {
+	db := storage.NewKeyValueStorage(hclog.NewNullLogger(), kvdb.NewMemoryKV()).(*storage.KeyValueStorage)
+	chain := blockchain.NewBlockchain(hclog.NewNullLogger(), db, nil)
+
+	genesis := &types.Block{Header: &types.Header{Number: 0}}
+	assert.NoError(t, chain.WriteGenesis(genesis))
+
+	handler := jsonrpc.NewWSHandler(hclog.NewNullLogger(), jsonrpc.NewEthEndpoint(chain), chain, db)
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	subscribeReq := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_subscribe","params":["logs",{}]}`)
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, subscribeReq))
+
+	_, _, err = conn.ReadMessage()
+	assert.NoError(t, err, "subscribe ack")
+
+	addr := types.Address{0xaa}
+
+	a1 := &types.Block{Header: &types.Header{Number: 1, ParentHash: genesis.Header.Hash}}
+	a1.Header.Hash[0] = 1
+	aReceipts := []*types.Receipt{{Logs: []*types.Log{{Address: addr}}}}
+	assert.NoError(t, chain.WriteBlock(a1, aReceipts))
+
+	// drain the newHeads-less logs notification for a1
+	_, _, err = conn.ReadMessage()
+	assert.NoError(t, err)
+
+	// b1 forks off genesis directly, replacing a1
+	b1 := &types.Block{Header: &types.Header{Number: 1, ParentHash: genesis.Header.Hash}}
+	b1.Header.Hash[0] = 2
+	bReceipts := []*types.Receipt{{Logs: []*types.Log{{Address: addr}}}}
+	assert.NoError(t, chain.WriteBlock(b1, bReceipts))
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	_, removedFrame, err := conn.ReadMessage()
+	assert.NoError(t, err)
+	_, addedFrame, err := conn.ReadMessage()
+	assert.NoError(t, err)
+
+	var removedNotif, addedNotif struct {
+		Params struct {
+			Result json.RawMessage `json:"result"`
+		} `json:"params"`
+	}
+	assert.NoError(t, json.Unmarshal(removedFrame, &removedNotif))
+	assert.NoError(t, json.Unmarshal(addedFrame, &addedNotif))
+
+	var removedLog, addedLog types.Log
+	assert.NoError(t, json.Unmarshal(removedNotif.Params.Result, &removedLog))
+	assert.NoError(t, json.Unmarshal(addedNotif.Params.Result, &addedLog))
+
+	assert.True(t, removedLog.Removed)
+	assert.False(t, addedLog.Removed)
+}
+
+// TestWS_EthGetLogs drives a real eth_getLogs JSON-RPC call over an actual
+// websocket connection, backed by a real Blockchain and ChainIndexer, and
+// checks that only the matching address's log comes back. EthEndpoint's
+// eth_getLogs is the non-test caller that makes
+// CandidateSections/MatchSections load-bearing; this test drives it end to
+// end instead of only through jsonrpc's own unit tests.
+func TestWS_EthGetLogs(t *testing.T) {
+	db := storage.NewKeyValueStorage(hclog.NewNullLogger(), kvdb.NewMemoryKV()).(*storage.KeyValueStorage)
+	chain := blockchain.NewBlockchain(hclog.NewNullLogger(), db, nil)
+
+	genesis := &types.Block{Header: &types.Header{Number: 0}}
+	assert.NoError(t, chain.WriteGenesis(genesis))
+
+	handler := jsonrpc.NewWSHandler(hclog.NewNullLogger(), jsonrpc.NewEthEndpoint(chain), chain, db)
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	match := types.Address{0xaa}
+	other := types.Address{0xbb}
+
+	b1 := &types.Block{Header: &types.Header{Number: 1, ParentHash: genesis.Header.Hash}}
+	b1.Header.Hash[0] = 1
+	receipts := []*types.Receipt{{Logs: []*types.Log{{Address: match}, {Address: other}}}}
+	assert.NoError(t, chain.WriteBlock(b1, receipts))
+
+	req := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_getLogs","params":[{"address":["0xaa00000000000000000000000000000000000000"]}]}`)
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, req))
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, frame, err := conn.ReadMessage()
+	assert.NoError(t, err)
+
+	var resp struct {
+		Result []*types.Log `json:"result"`
+	}
+	assert.NoError(t, json.Unmarshal(frame, &resp))
+	assert.Len(t, resp.Result, 1)
+	assert.Equal(t, match, resp.Result[0].Address)
+}
@@ -0,0 +1,87 @@
+package kvdb
+
+import (
+	"sync"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+)
+
+// MemoryKV is an in-memory storage.KV backend. It is the reference
+// implementation of the KV contract (Set/Get/Delete/Batch) and is what
+// tests reach for instead of standing up a real on-disk database.
+type MemoryKV struct {
+	lock sync.RWMutex
+	data map[string][]byte
+}
+
+// NewMemoryKV creates an empty in-memory KV store
+func NewMemoryKV() *MemoryKV {
+	return &MemoryKV{data: map[string][]byte{}}
+}
+
+// Close implements storage.KV
+func (m *MemoryKV) Close() error {
+	return nil
+}
+
+// Set implements storage.KV
+func (m *MemoryKV) Set(p, v []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.data[string(p)] = append([]byte{}, v...)
+	return nil
+}
+
+// Get implements storage.KV
+func (m *MemoryKV) Get(p []byte) ([]byte, bool, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	v, ok := m.data[string(p)]
+	if !ok {
+		return nil, false, nil
+	}
+	return append([]byte{}, v...), true, nil
+}
+
+// Delete implements storage.KV
+func (m *MemoryKV) Delete(p []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.data, string(p))
+	return nil
+}
+
+// Batch implements storage.KV. Queued writes are only applied to the
+// store on Write, so a batch that is never written (or errors out while
+// being built) leaves the store untouched.
+func (m *MemoryKV) Batch() storage.Batch {
+	return &memoryBatch{db: m}
+}
+
+type memoryWrite struct {
+	key   string
+	value []byte
+}
+
+type memoryBatch struct {
+	db     *MemoryKV
+	writes []memoryWrite
+}
+
+func (b *memoryBatch) Set(p, v []byte) error {
+	b.writes = append(b.writes, memoryWrite{key: string(p), value: append([]byte{}, v...)})
+	return nil
+}
+
+func (b *memoryBatch) Write() error {
+	b.db.lock.Lock()
+	defer b.db.lock.Unlock()
+
+	for _, w := range b.writes {
+		b.db.data[w.key] = w.value
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package jsonrpc
+
+import "encoding/json"
+
+// Request is a raw JSON-RPC 2.0 request
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      interface{}     `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+// Response is a raw JSON-RPC 2.0 response
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      interface{}     `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *ErrorObject    `json:"error,omitempty"`
+}
+
+// ErrorObject is a JSON-RPC 2.0 error payload
+type ErrorObject struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func newErrorResponse(id interface{}, code int, msg string) *Response {
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   &ErrorObject{Code: code, Message: msg},
+	}
+}
+
+func newResultResponse(id interface{}, result interface{}) *Response {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return newErrorResponse(id, -32603, err.Error())
+	}
+
+	return &Response{JSONRPC: "2.0", ID: id, Result: data}
+}
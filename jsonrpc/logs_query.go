@@ -0,0 +1,227 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"sort"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// sectionMatcher is the slice of blockchain.ChainIndexer that narrowing
+// down candidate sections needs
+type sectionMatcher interface {
+	MatchSections(bitIdxs []uint) ([]uint64, error)
+}
+
+// LogsQuery is the eth_getLogs request body: a LogFilter (shared with
+// eth_subscribe("logs")) plus the block range to scan. A nil FromBlock or
+// ToBlock defaults to the chain head, same as upstream eth_getLogs.
+type LogsQuery struct {
+	FromBlock *uint64 `json:"fromBlock"`
+	ToBlock   *uint64 `json:"toBlock"`
+	LogFilter
+}
+
+// LogsBackend is the slice of chain state eth_getLogs needs: resolving a
+// block number to its canonical header and receipts, and narrowing the
+// scan via the bloom-bits section index.
+type LogsBackend interface {
+	sectionMatcher
+	HeadNumber() uint64
+	HeaderByNumber(number uint64) (*types.Header, bool)
+	ReadReceipts(hash types.Hash) ([]*types.Receipt, bool)
+	SectionSize() uint64
+}
+
+// GetLogs implements eth_getLogs: it scans every block between query's
+// FromBlock and ToBlock, skipping any block whose bloom-bits section
+// CandidateSections rules out, and returns every log whose receipt
+// matches the filter.
+func GetLogs(backend LogsBackend, query *LogsQuery) ([]*types.Log, error) {
+	head := backend.HeadNumber()
+
+	from, to := head, head
+	if query.FromBlock != nil {
+		from = *query.FromBlock
+	}
+	if query.ToBlock != nil {
+		to = *query.ToBlock
+	}
+	if from > to {
+		return nil, fmt.Errorf("fromBlock %d is after toBlock %d", from, to)
+	}
+
+	sections, err := CandidateSections(backend, &query.LogFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidateSections map[uint64]bool
+	if sections != nil {
+		candidateSections = make(map[uint64]bool, len(sections))
+		for _, s := range sections {
+			candidateSections[s] = true
+		}
+	}
+
+	sectionSize := backend.SectionSize()
+
+	var logs []*types.Log
+	for n := from; n <= to; n++ {
+		if candidateSections != nil && !candidateSections[n/sectionSize] {
+			continue
+		}
+
+		header, ok := backend.HeaderByNumber(n)
+		if !ok {
+			continue
+		}
+
+		receipts, ok := backend.ReadReceipts(header.Hash)
+		if !ok {
+			continue
+		}
+
+		for _, receipt := range receipts {
+			for _, log := range receipt.Logs {
+				if query.LogFilter.Match(log) {
+					logs = append(logs, log)
+				}
+			}
+		}
+	}
+
+	return logs, nil
+}
+
+// CandidateSections returns the indexer sections that might contain a log
+// matching filter, so eth_getLogs can skip every other section instead of
+// reading and scanning each of their headers individually. A filter with
+// no addresses or topics matches everything and is reported by returning
+// (nil, nil) rather than forcing a scan of bit 0.
+//
+// Matching follows eth_getLogs semantics: filter.Addresses is an OR group
+// (any address may match) and each filter.Topics[i] is its own OR group
+// (any of that position's alternatives may match); the groups that are
+// present are then ANDed together, since a log must satisfy every
+// position the filter constrains. Flattening every bit into one AND, as
+// an earlier version of this function did, is wrong: it would require
+// every candidate address's bits to be set in the same section, silently
+// dropping logs from a section that only contains one of several
+// requested addresses.
+func CandidateSections(indexer sectionMatcher, filter *LogFilter) ([]uint64, error) {
+	groups := filterBloomGroups(filter)
+	if len(groups) == 0 {
+		return nil, nil
+	}
+
+	var result []uint64
+	for i, group := range groups {
+		hits, err := matchGroup(indexer, group)
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			result = hits
+			continue
+		}
+		result = intersectSections(result, hits)
+		if len(result) == 0 {
+			return nil, nil
+		}
+	}
+
+	return result, nil
+}
+
+// filterBloomGroups maps filter to its OR-of-AND matching structure: one
+// group for all addresses and one group per topic position, each holding the 3
+// bloom9 bits for every alternative value that position could take.
+func filterBloomGroups(filter *LogFilter) [][][3]uint {
+	var groups [][][3]uint
+
+	if len(filter.Addresses) != 0 {
+		group := make([][3]uint, len(filter.Addresses))
+		for i, addr := range filter.Addresses {
+			group[i] = bloom9Bits(addr.Bytes())
+		}
+		groups = append(groups, group)
+	}
+
+	for _, topics := range filter.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		group := make([][3]uint, len(topics))
+		for i, topic := range topics {
+			group[i] = bloom9Bits(topic.Bytes())
+		}
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// matchGroup returns the union of the sections matching any alternative
+// in group, since a single group is itself an OR of its alternatives.
+func matchGroup(indexer sectionMatcher, group [][3]uint) ([]uint64, error) {
+	seen := map[uint64]bool{}
+	var hits []uint64
+
+	for _, bits := range group {
+		sections, err := indexer.MatchSections(bits[:])
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range sections {
+			if !seen[s] {
+				seen[s] = true
+				hits = append(hits, s)
+			}
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool { return hits[i] < hits[j] })
+	return hits, nil
+}
+
+// intersectSections returns the sections present in both a and b, which
+// must each be sorted ascending
+func intersectSections(a, b []uint64) []uint64 {
+	inB := make(map[uint64]bool, len(b))
+	for _, s := range b {
+		inB[s] = true
+	}
+
+	var out []uint64
+	for _, s := range a {
+		if inB[s] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// bloom9Bits reproduces the 3 bit indices Ethereum's bloom9 sets for a
+// given address or topic: the low 11 bits of each of the first 3
+// (2-byte) big-endian chunks of its keccak256 hash.
+func bloom9Bits(data []byte) [3]uint {
+	hash := keccak256(data)
+
+	var bits [3]uint
+	for i := 0; i < 3; i++ {
+		bits[i] = (uint(hash[2*i])<<8 | uint(hash[2*i+1])) & (bloomBitLength - 1)
+	}
+	return bits
+}
+
+const bloomBitLength = 2048
+
+func keccak256(data []byte) []byte {
+	hw := sha3.NewLegacyKeccak256()
+	hw.Write(data)
+	return hw.Sum(nil)
+}
@@ -0,0 +1,148 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// fakeSectionMatcher records every MatchSections call it receives and
+// answers from hits in call order, so tests can assert the OR-of-AND
+// structure calls it the expected number of times with the expected bits.
+type fakeSectionMatcher struct {
+	calls [][]uint
+	hits  [][]uint64
+}
+
+func (f *fakeSectionMatcher) MatchSections(bitIdxs []uint) ([]uint64, error) {
+	idx := len(f.calls)
+	f.calls = append(f.calls, bitIdxs)
+	if idx < len(f.hits) {
+		return f.hits[idx], nil
+	}
+	return nil, nil
+}
+
+func TestCandidateSections_EmptyFilterSkipsIndexer(t *testing.T) {
+	matcher := &fakeSectionMatcher{}
+
+	sections, err := CandidateSections(matcher, &LogFilter{})
+	assert.NoError(t, err)
+	assert.Nil(t, sections)
+	assert.Nil(t, matcher.calls)
+}
+
+func TestCandidateSections_AddressDerivesThreeBits(t *testing.T) {
+	matcher := &fakeSectionMatcher{hits: [][]uint64{{2, 5}}}
+
+	filter := &LogFilter{Addresses: []types.Address{types.StringToAddress("1")}}
+	sections, err := CandidateSections(matcher, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{2, 5}, sections)
+	assert.Len(t, matcher.calls, 1)
+	assert.Len(t, matcher.calls[0], 3)
+	for _, b := range matcher.calls[0] {
+		assert.Less(t, b, uint(bloomBitLength))
+	}
+}
+
+func TestCandidateSections_MultipleAddressesAreUnioned(t *testing.T) {
+	// two addresses in the same filter are an OR: a section matching
+	// either one's bits is a candidate
+	matcher := &fakeSectionMatcher{hits: [][]uint64{{1, 2}, {2, 3}}}
+
+	filter := &LogFilter{Addresses: []types.Address{
+		types.StringToAddress("1"),
+		types.StringToAddress("2"),
+	}}
+	sections, err := CandidateSections(matcher, filter)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []uint64{1, 2, 3}, sections)
+}
+
+func TestCandidateSections_AddressAndTopicAreIntersected(t *testing.T) {
+	// an address group and a topic group are ANDed: only sections common
+	// to both survive
+	matcher := &fakeSectionMatcher{hits: [][]uint64{{1, 2, 3}, {2, 3, 4}}}
+
+	filter := &LogFilter{
+		Addresses: []types.Address{types.StringToAddress("1")},
+		Topics:    [][]types.Hash{{types.StringToHash("a")}},
+	}
+	sections, err := CandidateSections(matcher, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3}, sections)
+}
+
+func TestCandidateSections_NoOverlapReturnsNil(t *testing.T) {
+	matcher := &fakeSectionMatcher{hits: [][]uint64{{1}, {2}}}
+
+	filter := &LogFilter{
+		Addresses: []types.Address{types.StringToAddress("1")},
+		Topics:    [][]types.Hash{{types.StringToHash("a")}},
+	}
+	sections, err := CandidateSections(matcher, filter)
+	assert.NoError(t, err)
+	assert.Nil(t, sections)
+}
+
+type fakeLogsBackend struct {
+	fakeSectionMatcher
+	head        uint64
+	headers     map[uint64]*types.Header
+	receipts    map[types.Hash][]*types.Receipt
+	sectionSize uint64
+}
+
+func (b *fakeLogsBackend) HeadNumber() uint64 { return b.head }
+
+func (b *fakeLogsBackend) HeaderByNumber(number uint64) (*types.Header, bool) {
+	h, ok := b.headers[number]
+	return h, ok
+}
+
+func (b *fakeLogsBackend) ReadReceipts(hash types.Hash) ([]*types.Receipt, bool) {
+	r, ok := b.receipts[hash]
+	return r, ok
+}
+
+func (b *fakeLogsBackend) SectionSize() uint64 { return b.sectionSize }
+
+func TestGetLogs_ScansRangeAndAppliesFilter(t *testing.T) {
+	addr := types.StringToAddress("1")
+	other := types.StringToAddress("2")
+
+	h0 := &types.Header{Number: 0}
+	h0.Hash[0] = 1
+	h1 := &types.Header{Number: 1}
+	h1.Hash[0] = 2
+
+	match := &types.Log{Address: addr}
+	skip := &types.Log{Address: other}
+
+	backend := &fakeLogsBackend{
+		head:        1,
+		sectionSize: 4096,
+		headers:     map[uint64]*types.Header{0: h0, 1: h1},
+		receipts: map[types.Hash][]*types.Receipt{
+			h0.Hash: {{Logs: []*types.Log{match}}},
+			h1.Hash: {{Logs: []*types.Log{skip}}},
+		},
+	}
+
+	from := uint64(0)
+	query := &LogsQuery{FromBlock: &from, LogFilter: LogFilter{Addresses: []types.Address{addr}}}
+	logs, err := GetLogs(backend, query)
+	assert.NoError(t, err)
+	assert.Equal(t, []*types.Log{match}, logs)
+}
+
+func TestGetLogs_InvalidRange(t *testing.T) {
+	from, to := uint64(5), uint64(1)
+	backend := &fakeLogsBackend{sectionSize: 4096}
+
+	_, err := GetLogs(backend, &LogsQuery{FromBlock: &from, ToBlock: &to})
+	assert.Error(t, err)
+}
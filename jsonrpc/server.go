@@ -0,0 +1,54 @@
+package jsonrpc
+
+import (
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/go-hclog"
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// WSHandler is the http.Handler mounted at the node's websocket JSON-RPC
+// endpoint. It upgrades the connection and wraps it in a wsWrapper, which
+// is what actually dispatches eth_subscribe/eth_unsubscribe and forwards
+// everything else to next.
+type WSHandler struct {
+	logger   hclog.Logger
+	next     requestHandler
+	chain    chainEventSource
+	receipts receiptReader
+}
+
+// NewWSHandler creates the websocket JSON-RPC endpoint handler
+func NewWSHandler(logger hclog.Logger, next requestHandler, chain chainEventSource, receipts receiptReader) *WSHandler {
+	return &WSHandler{logger: logger.Named("jsonrpc-ws"), next: next, chain: chain, receipts: receipts}
+}
+
+// ServeHTTP implements http.Handler
+func (h *WSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		h.logger.Error("failed to upgrade websocket connection", "err", err)
+		return
+	}
+	defer conn.Close()
+
+	wrapper := newWSWrapper(h.logger, conn, h.next, h.chain, h.receipts)
+	defer wrapper.Close()
+
+	for {
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		if err := wrapper.WriteMessage(websocket.TextMessage, wrapper.Handle(raw)); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package jsonrpc
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// subscription kinds named by the eth_subscribe protocol. Only
+// NewHeadsSubscription and LogsSubscription are actually deliverable today
+// - handleChainEvent has nothing to drive the other two from (no tx pool,
+// no syncer progress feed) - so Subscribe rejects them rather than handing
+// back an id that will silently never fire.
+const (
+	NewHeadsSubscription               = "newHeads"
+	LogsSubscription                   = "logs"
+	NewPendingTransactionsSubscription = "newPendingTransactions"
+	SyncingSubscription                = "syncing"
+)
+
+// LogFilter narrows a "logs" subscription the same way eth_getLogs does: an
+// empty Addresses/Topics entry matches anything in that position
+type LogFilter struct {
+	Addresses []types.Address `json:"address"`
+	Topics    [][]types.Hash  `json:"topics"`
+}
+
+// Match reports whether log satisfies the filter
+func (f *LogFilter) Match(log *types.Log) bool {
+	if len(f.Addresses) != 0 {
+		found := false
+		for _, addr := range f.Addresses {
+			if addr == log.Address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(f.Topics) > len(log.Topics) {
+		return false
+	}
+	for i, topics := range f.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		found := false
+		for _, t := range topics {
+			if t == log.Topics[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// receiptReader is the slice of storage the logs subscription needs in
+// order to resolve the logs emitted by a newly canonical block
+type receiptReader interface {
+	ReadReceipts(hash types.Hash) ([]*types.Receipt, bool)
+}
+
+// chainEventSource is implemented by blockchain.Blockchain
+type chainEventSource interface {
+	SubscribeChainEvents() *blockchain.Subscription
+}
+
+// subscription is a single live eth_subscribe registration on a connection
+type subscription struct {
+	id     string
+	kind   string
+	filter *LogFilter
+}
+
+type subscriptionNotification struct {
+	JSONRPC string                  `json:"jsonrpc"`
+	Method  string                  `json:"method"`
+	Params  subscriptionNotifyParams `json:"params"`
+}
+
+type subscriptionNotifyParams struct {
+	Subscription string          `json:"subscription"`
+	Result       json.RawMessage `json:"result"`
+}
+
+// wsConn is the slice of *websocket.Conn the hub needs; satisfied directly
+// by a real connection and easily faked in tests
+type wsConn interface {
+	WriteMessage(messageType int, data []byte) error
+}
+
+// SubscriptionHub tracks every live eth_subscribe registration for a single
+// websocket connection and pushes eth_subscription notifications to it. One
+// hub is created per connection and torn down when the socket closes.
+type SubscriptionHub struct {
+	logger hclog.Logger
+
+	conn     wsConn
+	receipts receiptReader
+
+	lock sync.Mutex
+	subs map[string]*subscription
+
+	chainSub *blockchain.Subscription
+	closeCh  chan struct{}
+}
+
+// NewSubscriptionHub wires a per-connection subscription hub to the chain's
+// canonical head feed
+func NewSubscriptionHub(logger hclog.Logger, conn wsConn, chain chainEventSource, receipts receiptReader) *SubscriptionHub {
+	h := &SubscriptionHub{
+		logger:   logger.Named("subscriptions"),
+		conn:     conn,
+		receipts: receipts,
+		subs:     map[string]*subscription{},
+		chainSub: chain.SubscribeChainEvents(),
+		closeCh:  make(chan struct{}),
+	}
+
+	go h.run()
+
+	return h
+}
+
+// Close tears down every subscription on this connection. Call it when the
+// underlying websocket closes.
+func (h *SubscriptionHub) Close() {
+	close(h.closeCh)
+}
+
+// Subscribe registers a new eth_subscribe of the given kind and returns its
+// opaque subscription id. Only NewHeadsSubscription and LogsSubscription
+// are implemented: kind NewPendingTransactionsSubscription or
+// SyncingSubscription returns an error rather than a working subscription -
+// this package has no tx pool or sync-progress feed to drive them from.
+func (h *SubscriptionHub) Subscribe(kind string, filter *LogFilter) (string, error) {
+	switch kind {
+	case NewHeadsSubscription, LogsSubscription:
+	case NewPendingTransactionsSubscription, SyncingSubscription:
+		return "", fmt.Errorf("subscription kind %q is not yet supported", kind)
+	default:
+		return "", fmt.Errorf("unknown subscription kind %q", kind)
+	}
+
+	id, err := newSubscriptionID()
+	if err != nil {
+		return "", err
+	}
+
+	h.lock.Lock()
+	h.subs[id] = &subscription{id: id, kind: kind, filter: filter}
+	h.lock.Unlock()
+
+	return id, nil
+}
+
+// Unsubscribe removes a subscription and reports whether id was known
+func (h *SubscriptionHub) Unsubscribe(id string) bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if _, ok := h.subs[id]; !ok {
+		return false
+	}
+
+	delete(h.subs, id)
+	return true
+}
+
+func newSubscriptionID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "0x" + hex.EncodeToString(buf), nil
+}
+
+func (h *SubscriptionHub) run() {
+	defer h.chainSub.Close()
+
+	for {
+		select {
+		case evnt := <-h.chainSub.Event():
+			h.handleChainEvent(evnt)
+		case <-h.closeCh:
+			return
+		}
+	}
+}
+
+func (h *SubscriptionHub) handleChainEvent(evnt *blockchain.ChainEvent) {
+	h.lock.Lock()
+	subs := make([]*subscription, 0, len(h.subs))
+	for _, s := range h.subs {
+		subs = append(subs, s)
+	}
+	h.lock.Unlock()
+
+	for _, s := range subs {
+		switch s.kind {
+		case NewHeadsSubscription:
+			// only the added side of the chain is pushed on newHeads, matching
+			// the upstream eth_subscribe semantics
+			for _, header := range evnt.Added {
+				h.notify(s.id, header)
+			}
+		case LogsSubscription:
+			h.notifyLogs(s, evnt)
+		}
+	}
+}
+
+func (h *SubscriptionHub) notifyLogs(s *subscription, evnt *blockchain.ChainEvent) {
+	for _, header := range evnt.Removed {
+		h.pushLogsForHeader(s, header, true)
+	}
+	for _, header := range evnt.Added {
+		h.pushLogsForHeader(s, header, false)
+	}
+}
+
+func (h *SubscriptionHub) pushLogsForHeader(s *subscription, header *types.Header, removed bool) {
+	receipts, ok := h.receipts.ReadReceipts(header.Hash)
+	if !ok {
+		return
+	}
+
+	for _, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			log.Removed = removed
+			if s.filter != nil && !s.filter.Match(log) {
+				continue
+			}
+			h.notify(s.id, log)
+		}
+	}
+}
+
+func (h *SubscriptionHub) notify(id string, result interface{}) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		h.logger.Error("failed to marshal subscription result", "err", err)
+		return
+	}
+
+	frame := &subscriptionNotification{
+		JSONRPC: "2.0",
+		Method:  "eth_subscription",
+		Params: subscriptionNotifyParams{
+			Subscription: id,
+			Result:       data,
+		},
+	}
+
+	raw, err := json.Marshal(frame)
+	if err != nil {
+		h.logger.Error("failed to marshal subscription notification", "err", err)
+		return
+	}
+
+	if err := h.conn.WriteMessage(websocket.TextMessage, raw); err != nil {
+		h.logger.Error("failed to write subscription notification", "err", err)
+	}
+}
@@ -0,0 +1,122 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+type fakeChain struct {
+	sub *blockchain.Subscription
+}
+
+func (f *fakeChain) SubscribeChainEvents() *blockchain.Subscription {
+	return f.sub
+}
+
+type fakeReceipts struct {
+	m map[types.Hash][]*types.Receipt
+}
+
+func (f *fakeReceipts) ReadReceipts(hash types.Hash) ([]*types.Receipt, bool) {
+	r, ok := f.m[hash]
+	return r, ok
+}
+
+type fakeConn struct {
+	lock   sync.Mutex
+	frames [][]byte
+}
+
+func (f *fakeConn) WriteMessage(_ int, data []byte) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.frames = append(f.frames, append([]byte{}, data...))
+	return nil
+}
+
+func (f *fakeConn) snapshot() [][]byte {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return append([][]byte{}, f.frames...)
+}
+
+func TestSubscriptionHub_SubscribeUnsubscribe(t *testing.T) {
+	chain := &fakeChain{sub: blockchain.NewTestSubscription()}
+	hub := NewSubscriptionHub(hclog.NewNullLogger(), &fakeConn{}, chain, &fakeReceipts{})
+	defer hub.Close()
+
+	id, err := hub.Subscribe(NewHeadsSubscription, nil)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, id)
+
+	assert.True(t, hub.Unsubscribe(id))
+	assert.False(t, hub.Unsubscribe(id))
+
+	_, err = hub.Subscribe("bogus", nil)
+	assert.Error(t, err)
+}
+
+func TestSubscriptionHub_RejectsUnsupportedKinds(t *testing.T) {
+	chain := &fakeChain{sub: blockchain.NewTestSubscription()}
+	hub := NewSubscriptionHub(hclog.NewNullLogger(), &fakeConn{}, chain, &fakeReceipts{})
+	defer hub.Close()
+
+	_, err := hub.Subscribe(NewPendingTransactionsSubscription, nil)
+	assert.Error(t, err)
+
+	_, err = hub.Subscribe(SyncingSubscription, nil)
+	assert.Error(t, err)
+}
+
+func TestSubscriptionHub_LogsOrderingAcrossReorg(t *testing.T) {
+	sub := blockchain.NewTestSubscription()
+	chain := &fakeChain{sub: sub}
+
+	removedHash := types.Hash{1}
+	addedHash := types.Hash{2}
+
+	receipts := &fakeReceipts{m: map[types.Hash][]*types.Receipt{
+		removedHash: {{Logs: []*types.Log{{Address: types.Address{0xaa}}}}},
+		addedHash:   {{Logs: []*types.Log{{Address: types.Address{0xaa}}}}},
+	}}
+
+	conn := &fakeConn{}
+	hub := NewSubscriptionHub(hclog.NewNullLogger(), conn, chain, receipts)
+	defer hub.Close()
+
+	id, err := hub.Subscribe(LogsSubscription, nil)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, id)
+
+	blockchain.PushTestChainEvent(sub, &blockchain.ChainEvent{
+		Removed: []*types.Header{{Hash: removedHash}},
+		Added:   []*types.Header{{Hash: addedHash}},
+	})
+
+	var frames [][]byte
+	assert.Eventually(t, func() bool {
+		frames = conn.snapshot()
+		return len(frames) == 2
+	}, time.Second, 5*time.Millisecond)
+
+	var first, second subscriptionNotification
+	assert.NoError(t, json.Unmarshal(frames[0], &first))
+	assert.NoError(t, json.Unmarshal(frames[1], &second))
+
+	var firstLog, secondLog types.Log
+	assert.NoError(t, json.Unmarshal(first.Params.Result, &firstLog))
+	assert.NoError(t, json.Unmarshal(second.Params.Result, &secondLog))
+
+	assert.True(t, firstLog.Removed)
+	assert.False(t, secondLog.Removed)
+}
@@ -0,0 +1,112 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/go-hclog"
+)
+
+// requestHandler is the rest of the JSON-RPC dispatch chain; every method
+// other than eth_subscribe/eth_unsubscribe is delegated to it unchanged
+type requestHandler interface {
+	Handle(req Request) *Response
+}
+
+// wsWrapper adapts a single websocket connection to the JSON-RPC dispatcher,
+// intercepting eth_subscribe/eth_unsubscribe so subscriptions are tracked
+// per connection instead of globally, and torn down when the socket closes
+type wsWrapper struct {
+	logger hclog.Logger
+	conn   *websocket.Conn
+	next   requestHandler
+
+	writeLock sync.Mutex
+	hub       *SubscriptionHub
+}
+
+func newWSWrapper(logger hclog.Logger, conn *websocket.Conn, next requestHandler, chain chainEventSource, receipts receiptReader) *wsWrapper {
+	w := &wsWrapper{logger: logger.Named("ws"), conn: conn, next: next}
+	w.hub = NewSubscriptionHub(logger, w, chain, receipts)
+
+	return w
+}
+
+// WriteMessage serializes writes to the connection so subscription pushes
+// and request/response frames never interleave on the wire
+func (w *wsWrapper) WriteMessage(messageType int, data []byte) error {
+	w.writeLock.Lock()
+	defer w.writeLock.Unlock()
+
+	return w.conn.WriteMessage(messageType, data)
+}
+
+// Close tears down every subscription registered on this connection
+func (w *wsWrapper) Close() {
+	w.hub.Close()
+}
+
+// Handle processes a single JSON-RPC frame read off the socket
+func (w *wsWrapper) Handle(raw []byte) []byte {
+	var req Request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return mustMarshal(newErrorResponse(nil, -32700, "parse error"))
+	}
+
+	var resp *Response
+	switch req.Method {
+	case "eth_subscribe":
+		resp = w.handleSubscribe(&req)
+	case "eth_unsubscribe":
+		resp = w.handleUnsubscribe(&req)
+	default:
+		resp = w.next.Handle(req)
+	}
+
+	return mustMarshal(resp)
+}
+
+func (w *wsWrapper) handleSubscribe(req *Request) *Response {
+	var params []json.RawMessage
+	if err := json.Unmarshal(req.Params, &params); err != nil || len(params) == 0 {
+		return newErrorResponse(req.ID, -32602, "invalid subscribe params")
+	}
+
+	var kind string
+	if err := json.Unmarshal(params[0], &kind); err != nil {
+		return newErrorResponse(req.ID, -32602, "invalid subscription kind")
+	}
+
+	var filter *LogFilter
+	if kind == LogsSubscription && len(params) > 1 {
+		filter = &LogFilter{}
+		if err := json.Unmarshal(params[1], filter); err != nil {
+			return newErrorResponse(req.ID, -32602, "invalid log filter")
+		}
+	}
+
+	id, err := w.hub.Subscribe(kind, filter)
+	if err != nil {
+		return newErrorResponse(req.ID, -32602, err.Error())
+	}
+
+	return newResultResponse(req.ID, id)
+}
+
+func (w *wsWrapper) handleUnsubscribe(req *Request) *Response {
+	var params []string
+	if err := json.Unmarshal(req.Params, &params); err != nil || len(params) != 1 {
+		return newErrorResponse(req.ID, -32602, "invalid unsubscribe params")
+	}
+
+	return newResultResponse(req.ID, w.hub.Unsubscribe(params[0]))
+}
+
+func mustMarshal(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return []byte(`{"jsonrpc":"2.0","error":{"code":-32603,"message":"internal error"}}`)
+	}
+	return data
+}
@@ -0,0 +1,100 @@
+package syncer
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// BodyPeer is the slice of a peer connection the body/receipt fetchers need
+type BodyPeer interface {
+	GetBodies(hashes []types.Hash) ([]*types.Body, error)
+	GetReceipts(hashes []types.Hash) ([][]*types.Receipt, error)
+}
+
+// FetchBodiesAndReceipts downloads the body and receipts for every header
+// that actually has one (TxRoot/ReceiptsRoot != empty), validates each body
+// against its header via a CalcUncleHash check, and persists everything
+// through a single atomic batch.
+func FetchBodiesAndReceipts(peer BodyPeer, db *storage.KeyValueStorage, headers []*types.Header) error {
+	var withBody, withReceipts []*types.Header
+	for _, h := range headers {
+		if h.HasBody() {
+			withBody = append(withBody, h)
+		}
+		if h.HasReceipts() {
+			withReceipts = append(withReceipts, h)
+		}
+	}
+
+	bodies, err := fetchBodies(peer, withBody)
+	if err != nil {
+		return err
+	}
+
+	receipts, err := fetchReceipts(peer, withReceipts)
+	if err != nil {
+		return err
+	}
+
+	batch := db.NewBatch()
+	for i, h := range withBody {
+		if err := validateUncleHash(h, bodies[i]); err != nil {
+			return err
+		}
+		batch.PutBody(h.Hash, bodies[i])
+	}
+	for i, h := range withReceipts {
+		batch.PutReceipts(h.Hash, receipts[i])
+	}
+
+	return batch.WriteBatch()
+}
+
+func fetchBodies(peer BodyPeer, headers []*types.Header) ([]*types.Body, error) {
+	if len(headers) == 0 {
+		return nil, nil
+	}
+
+	bodies, err := peer.GetBodies(hashesOf(headers))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bodies: %w", err)
+	}
+	if len(bodies) != len(headers) {
+		return nil, fmt.Errorf("peer returned %d bodies for %d headers", len(bodies), len(headers))
+	}
+
+	return bodies, nil
+}
+
+func fetchReceipts(peer BodyPeer, headers []*types.Header) ([][]*types.Receipt, error) {
+	if len(headers) == 0 {
+		return nil, nil
+	}
+
+	receipts, err := peer.GetReceipts(hashesOf(headers))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch receipts: %w", err)
+	}
+	if len(receipts) != len(headers) {
+		return nil, fmt.Errorf("peer returned %d receipt sets for %d headers", len(receipts), len(headers))
+	}
+
+	return receipts, nil
+}
+
+func hashesOf(headers []*types.Header) []types.Hash {
+	hashes := make([]types.Hash, len(headers))
+	for i, h := range headers {
+		hashes[i] = h.Hash
+	}
+	return hashes
+}
+
+func validateUncleHash(h *types.Header, body *types.Body) error {
+	if uncleHash := types.CalcUncleHash(body.Uncles); uncleHash != h.Sha3Uncles {
+		return fmt.Errorf("uncle hash mismatch for block %s: want %s, got %s", h.Hash, h.Sha3Uncles, uncleHash)
+	}
+	return nil
+}
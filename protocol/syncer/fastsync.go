@@ -0,0 +1,121 @@
+package syncer
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+)
+
+// Peer bundles every capability the fast syncer needs from a remote peer
+type Peer interface {
+	HeaderPeer
+	BodyPeer
+	NodePeer
+}
+
+// FastSyncer drives an eth/63-style fast sync: headers and bodies/receipts
+// are downloaded for the whole chain, but only the pivot block's state
+// trie is downloaded instead of executed block by block. Once the pivot's
+// trie is complete, syncing falls back to full sync for later blocks.
+type FastSyncer struct {
+	logger hclog.Logger
+	db     *storage.KeyValueStorage
+
+	pivotDepth uint64
+}
+
+// NewFastSyncer creates a fast syncer that keeps its pivot pivotDepth
+// blocks behind the best known remote header (0 uses DefaultPivotDepth)
+func NewFastSyncer(logger hclog.Logger, db *storage.KeyValueStorage, pivotDepth uint64) *FastSyncer {
+	return &FastSyncer{logger: logger.Named("fastsync"), db: db, pivotDepth: pivotDepth}
+}
+
+// Sync runs (or resumes) a fast sync against peer up to bestHeader and
+// returns the pivot block number it switched back to full sync at.
+func (f *FastSyncer) Sync(peer Peer, bestHeader uint64) (uint64, error) {
+	progress, resuming := LoadFastSyncProgress(f.db)
+	if !resuming {
+		progress = &FastSyncProgress{Pivot: SelectPivot(bestHeader, f.pivotDepth)}
+		if err := SaveFastSyncProgress(f.db, progress); err != nil {
+			return 0, err
+		}
+	} else {
+		f.logger.Info("resuming fast sync", "pivot", progress.Pivot, "headerHead", progress.HeaderHead)
+	}
+
+	skeleton := NewSkeletonDownloader([]HeaderPeer{peer}, DefaultSkeletonChunkSize)
+
+	for progress.HeaderHead < bestHeader {
+		// HeaderHead == 0 means nothing has been synced yet, so genesis
+		// itself is still owed a download; any other HeaderHead is a
+		// block already downloaded and written in a previous chunk.
+		start := progress.HeaderHead
+		if start > 0 {
+			start++
+		}
+
+		chunkEnd := start + DefaultSkeletonChunkSize - 1
+		if chunkEnd > bestHeader {
+			chunkEnd = bestHeader
+		}
+
+		headers, err := skeleton.Download(start, chunkEnd)
+		if err != nil {
+			return 0, err
+		}
+
+		if err := FetchBodiesAndReceipts(peer, f.db, headers); err != nil {
+			return 0, err
+		}
+
+		for _, h := range headers {
+			parentDiff, ok := f.db.ReadDiff(h.ParentHash)
+			if !ok {
+				parentDiff = big.NewInt(0)
+			}
+			diff := new(big.Int).Add(parentDiff, new(big.Int).SetUint64(h.Difficulty))
+
+			if err := f.db.WriteCanonicalHeader(h, diff); err != nil {
+				return 0, err
+			}
+		}
+
+		progress.HeaderHead = chunkEnd
+		if err := SaveFastSyncProgress(f.db, progress); err != nil {
+			return 0, err
+		}
+	}
+
+	if !progress.PivotSynced {
+		if err := f.syncPivotState(peer, progress.Pivot); err != nil {
+			return 0, err
+		}
+
+		progress.PivotSynced = true
+		if err := SaveFastSyncProgress(f.db, progress); err != nil {
+			return 0, err
+		}
+	}
+
+	f.logger.Info("fast sync reached pivot, switching to full sync", "pivot", progress.Pivot)
+
+	return progress.Pivot, nil
+}
+
+func (f *FastSyncer) syncPivotState(peer Peer, pivot uint64) error {
+	pivotHash, ok := f.db.ReadCanonicalHash(pivot)
+	if !ok {
+		return fmt.Errorf("missing canonical hash for pivot %d", pivot)
+	}
+
+	pivotHeader, ok := f.db.ReadHeader(pivotHash)
+	if !ok {
+		return fmt.Errorf("missing pivot header %s", pivotHash)
+	}
+
+	trieSyncer := NewTrieSyncer(peer, storageStateDB{db: f.db})
+	return trieSyncer.Sync(pivotHeader.StateRoot)
+}
@@ -0,0 +1,138 @@
+package syncer
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+func TestSelectPivot(t *testing.T) {
+	assert.Equal(t, uint64(0), SelectPivot(10, 64))
+	assert.Equal(t, uint64(36), SelectPivot(100, 64))
+	assert.Equal(t, uint64(900), SelectPivot(964, 64))
+}
+
+type fakeHeaderPeer struct {
+	id      string
+	headers map[uint64]*types.Header
+	calls   *[]string
+}
+
+func (p *fakeHeaderPeer) GetHeaders(number uint64, chunkSize uint64) ([]*types.Header, error) {
+	*p.calls = append(*p.calls, p.id)
+
+	headers := make([]*types.Header, 0, chunkSize)
+	for i := uint64(0); i < chunkSize; i++ {
+		h, ok := p.headers[number+i]
+		if !ok {
+			break
+		}
+		headers = append(headers, h)
+	}
+	return headers, nil
+}
+
+func buildLinkedHeaders(n int) map[uint64]*types.Header {
+	headers := map[uint64]*types.Header{}
+
+	var parent types.Hash
+	for i := 0; i < n; i++ {
+		h := &types.Header{Number: uint64(i), ParentHash: parent, Sha3Uncles: types.EmptyUncleHash}
+		h.Hash[0] = byte(i + 1)
+		headers[h.Number] = h
+		parent = h.Hash
+	}
+
+	return headers
+}
+
+func TestSkeletonDownloader_FansOutAndStitches(t *testing.T) {
+	headers := buildLinkedHeaders(10)
+
+	var calls []string
+	peerA := &fakeHeaderPeer{id: "A", headers: headers, calls: &calls}
+	peerB := &fakeHeaderPeer{id: "B", headers: headers, calls: &calls}
+
+	d := NewSkeletonDownloader([]HeaderPeer{peerA, peerB}, 3)
+
+	got, err := d.Download(0, 9)
+	assert.NoError(t, err)
+	assert.Len(t, got, 10)
+	for i, h := range got {
+		assert.Equal(t, uint64(i), h.Number)
+	}
+
+	assert.Contains(t, calls, "A")
+	assert.Contains(t, calls, "B")
+}
+
+func TestSkeletonDownloader_RejectsBrokenChain(t *testing.T) {
+	headers := buildLinkedHeaders(6)
+	headers[3].ParentHash = types.Hash{0xff}
+
+	var calls []string
+	peer := &fakeHeaderPeer{id: "A", headers: headers, calls: &calls}
+
+	d := NewSkeletonDownloader([]HeaderPeer{peer}, 3)
+
+	_, err := d.Download(0, 5)
+	assert.Error(t, err)
+}
+
+type fakeBatch struct {
+	db *fakeKV
+}
+
+func (b *fakeBatch) Set(p, v []byte) error {
+	b.db.data[string(p)] = append([]byte{}, v...)
+	return nil
+}
+
+func (b *fakeBatch) Write() error { return nil }
+
+type fakeKV struct {
+	data map[string][]byte
+}
+
+func newFakeKV() *fakeKV {
+	return &fakeKV{data: map[string][]byte{}}
+}
+
+func (k *fakeKV) Close() error { return nil }
+
+func (k *fakeKV) Set(p, v []byte) error {
+	k.data[string(p)] = append([]byte{}, v...)
+	return nil
+}
+
+func (k *fakeKV) Get(p []byte) ([]byte, bool, error) {
+	v, ok := k.data[string(p)]
+	return v, ok, nil
+}
+
+func (k *fakeKV) Delete(p []byte) error {
+	delete(k.data, string(p))
+	return nil
+}
+
+func (k *fakeKV) Batch() storage.Batch {
+	return &fakeBatch{db: k}
+}
+
+func TestFastSyncProgress_SaveLoadRoundtrip(t *testing.T) {
+	db := storage.NewKeyValueStorage(hclog.NewNullLogger(), newFakeKV()).(*storage.KeyValueStorage)
+
+	_, ok := LoadFastSyncProgress(db)
+	assert.False(t, ok)
+
+	want := &FastSyncProgress{Pivot: 900, HeaderHead: 500, PivotSynced: false}
+	assert.NoError(t, SaveFastSyncProgress(db, want))
+
+	got, ok := LoadFastSyncProgress(db)
+	assert.True(t, ok)
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,21 @@
+package syncer
+
+import "github.com/0xPolygon/polygon-sdk/types"
+
+// Protocol message types added alongside the existing body/receipt requests
+// to support fast sync's trie-node downloads
+const (
+	MessageGetNodeData = "GetNodeData"
+	MessageNodeData    = "NodeData"
+)
+
+// GetNodeDataRequest asks a peer for the raw trie node data behind each hash
+type GetNodeDataRequest struct {
+	Hashes []types.Hash
+}
+
+// NodeDataResponse carries the raw node bytes for a GetNodeDataRequest, in
+// the same order as the hashes that were requested
+type NodeDataResponse struct {
+	Nodes [][]byte
+}
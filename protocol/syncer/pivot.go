@@ -0,0 +1,17 @@
+package syncer
+
+// DefaultPivotDepth is how many blocks behind the best known header the
+// fast-sync pivot sits by default (eth/63's "head - 64")
+const DefaultPivotDepth = 64
+
+// SelectPivot picks the fast-sync pivot block number: depth blocks behind
+// head, floored at 0
+func SelectPivot(head uint64, depth uint64) uint64 {
+	if depth == 0 {
+		depth = DefaultPivotDepth
+	}
+	if head < depth {
+		return 0
+	}
+	return head - depth
+}
@@ -0,0 +1,42 @@
+package syncer
+
+import (
+	"encoding/json"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+)
+
+// FastSyncProgress is the resumable state of an in-flight fast sync
+type FastSyncProgress struct {
+	Pivot       uint64 `json:"pivot"`
+	HeaderHead  uint64 `json:"headerHead"`
+	PivotSynced bool   `json:"pivotSynced"`
+}
+
+// LoadFastSyncProgress returns any in-flight fast-sync progress the node
+// was interrupted during, so it can resume instead of restarting from
+// scratch
+func LoadFastSyncProgress(db *storage.KeyValueStorage) (*FastSyncProgress, bool) {
+	data, ok := db.ReadFastSyncState()
+	if !ok {
+		return nil, false
+	}
+
+	progress := &FastSyncProgress{}
+	if err := json.Unmarshal(data, progress); err != nil {
+		return nil, false
+	}
+
+	return progress, true
+}
+
+// SaveFastSyncProgress persists progress so an interrupted fast sync can
+// resume instead of restarting
+func SaveFastSyncProgress(db *storage.KeyValueStorage, progress *FastSyncProgress) error {
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+
+	return db.WriteFastSyncState(data)
+}
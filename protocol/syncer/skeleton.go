@@ -0,0 +1,82 @@
+package syncer
+
+import (
+	"fmt"
+
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// DefaultSkeletonChunkSize is the number of headers requested from a single
+// peer per GetHeaders round
+const DefaultSkeletonChunkSize = 192
+
+// HeaderPeer is the slice of a peer connection the skeleton downloader needs
+type HeaderPeer interface {
+	// GetHeaders requests chunkSize headers starting at number (inclusive)
+	GetHeaders(number uint64, chunkSize uint64) ([]*types.Header, error)
+}
+
+// SkeletonDownloader fetches headers in fixed-size chunks, fanning requests
+// out across the given peers and stitching the results back together into
+// a single, parent-linked chain
+type SkeletonDownloader struct {
+	peers     []HeaderPeer
+	chunkSize uint64
+}
+
+// NewSkeletonDownloader creates a downloader that round-robins chunkSize
+// requests across peers (0 uses DefaultSkeletonChunkSize)
+func NewSkeletonDownloader(peers []HeaderPeer, chunkSize uint64) *SkeletonDownloader {
+	if chunkSize == 0 {
+		chunkSize = DefaultSkeletonChunkSize
+	}
+	return &SkeletonDownloader{peers: peers, chunkSize: chunkSize}
+}
+
+// Download fetches every header in [from, to], round-robining requests
+// across peers, and returns them stitched in ascending order
+func (d *SkeletonDownloader) Download(from, to uint64) ([]*types.Header, error) {
+	if len(d.peers) == 0 {
+		return nil, fmt.Errorf("no peers available for header download")
+	}
+
+	var headers []*types.Header
+	peerIdx := 0
+
+	for number := from; number <= to; number += d.chunkSize {
+		chunk := d.chunkSize
+		if remaining := to - number + 1; remaining < chunk {
+			chunk = remaining
+		}
+
+		peer := d.peers[peerIdx%len(d.peers)]
+		peerIdx++
+
+		got, err := peer.GetHeaders(number, chunk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch headers [%d, %d): %w", number, number+chunk, err)
+		}
+
+		if err := stitch(headers, got); err != nil {
+			return nil, err
+		}
+		headers = append(headers, got...)
+	}
+
+	return headers, nil
+}
+
+// stitch verifies that got continues on from the end of headers without a
+// gap, so an out-of-order or missing response is caught immediately
+func stitch(headers, got []*types.Header) error {
+	if len(headers) == 0 || len(got) == 0 {
+		return nil
+	}
+
+	last := headers[len(headers)-1]
+	first := got[0]
+	if first.ParentHash != last.Hash {
+		return fmt.Errorf("header chunk does not link to parent: want parent %s, got %s", last.Hash, first.ParentHash)
+	}
+	return nil
+}
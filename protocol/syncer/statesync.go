@@ -0,0 +1,204 @@
+package syncer
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// DefaultNodeBatchSize is the number of trie node hashes requested per
+// GetNodeData round
+const DefaultNodeBatchSize = 384
+
+// StateDB is the slice of the state backend the trie downloader writes
+// fetched nodes into, keyed by node hash
+type StateDB interface {
+	Get(hash types.Hash) ([]byte, bool)
+	Put(hash types.Hash, data []byte) error
+}
+
+// storageStateDB adapts storage.KeyValueStorage to StateDB, so fetched
+// trie nodes land somewhere durable instead of being discarded.
+type storageStateDB struct {
+	db *storage.KeyValueStorage
+}
+
+func (s storageStateDB) Get(hash types.Hash) ([]byte, bool) { return s.db.ReadStateNode(hash) }
+
+func (s storageStateDB) Put(hash types.Hash, data []byte) error {
+	return s.db.WriteStateNode(hash, data)
+}
+
+// NodePeer is the slice of a peer connection the trie downloader needs
+type NodePeer interface {
+	// GetNodeData requests the raw trie node data behind each hash
+	GetNodeData(hashes []types.Hash) ([][]byte, error)
+}
+
+// TrieSyncer walks a state trie from its root, downloading every node the
+// local state DB does not already have. Each round's unknown hashes are
+// split into batchSize-sized chunks and fetched concurrently, since the
+// chunks are independent requests to begin with.
+type TrieSyncer struct {
+	peer NodePeer
+	db   StateDB
+
+	batchSize int
+}
+
+// NewTrieSyncer creates a trie syncer that requests at most
+// DefaultNodeBatchSize unknown nodes per GetNodeData call
+func NewTrieSyncer(peer NodePeer, db StateDB) *TrieSyncer {
+	return &TrieSyncer{peer: peer, db: db, batchSize: DefaultNodeBatchSize}
+}
+
+// Sync downloads every unknown node reachable from root and writes it to
+// the state DB, returning once the whole sub-trie is present locally
+func (t *TrieSyncer) Sync(root types.Hash) error {
+	frontier := []types.Hash{root}
+
+	for len(frontier) > 0 {
+		unknown := frontier[:0]
+		for _, hash := range frontier {
+			if _, ok := t.db.Get(hash); !ok {
+				unknown = append(unknown, hash)
+			}
+		}
+		if len(unknown) == 0 {
+			return nil
+		}
+
+		children, err := t.fetchRound(unknown)
+		if err != nil {
+			return err
+		}
+
+		frontier = children
+	}
+
+	return nil
+}
+
+// fetchRound fetches every hash in unknown, split across concurrent
+// batchSize-sized requests, and returns the union of their children.
+func (t *TrieSyncer) fetchRound(unknown []types.Hash) ([]types.Hash, error) {
+	chunks := chunkHashes(unknown, t.batchSize)
+
+	var (
+		wg       sync.WaitGroup
+		lock     sync.Mutex
+		children []types.Hash
+		firstErr error
+	)
+
+	record := func(err error) {
+		lock.Lock()
+		defer lock.Unlock()
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	for _, chunk := range chunks {
+		wg.Add(1)
+		go func(chunk []types.Hash) {
+			defer wg.Done()
+
+			kids, err := t.fetchChunk(chunk)
+			if err != nil {
+				record(err)
+				return
+			}
+
+			lock.Lock()
+			children = append(children, kids...)
+			lock.Unlock()
+		}(chunk)
+	}
+	wg.Wait()
+
+	return children, firstErr
+}
+
+func (t *TrieSyncer) fetchChunk(chunk []types.Hash) ([]types.Hash, error) {
+	nodes, err := t.peer.GetNodeData(chunk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch trie nodes: %w", err)
+	}
+	if len(nodes) != len(chunk) {
+		return nil, fmt.Errorf("peer returned %d nodes for %d requested hashes", len(nodes), len(chunk))
+	}
+
+	var children []types.Hash
+	for i, hash := range chunk {
+		if err := t.db.Put(hash, nodes[i]); err != nil {
+			return nil, err
+		}
+
+		kids, err := decodeTrieNodeChildren(nodes[i])
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, kids...)
+	}
+
+	return children, nil
+}
+
+func chunkHashes(hashes []types.Hash, size int) [][]types.Hash {
+	var chunks [][]types.Hash
+	for len(hashes) > 0 {
+		n := size
+		if n > len(hashes) {
+			n = len(hashes)
+		}
+		chunks = append(chunks, hashes[:n])
+		hashes = hashes[n:]
+	}
+	return chunks
+}
+
+// decodeTrieNodeChildren extracts the child node hashes referenced by a
+// raw Merkle-Patricia trie node: a 17-element list (16 branch slots plus
+// a value) or a 2-element list (an extension/leaf's path plus its
+// value). A child slot holding fewer than 32 bytes is the node embedded
+// inline rather than a hash reference, and is skipped since there is
+// nothing further to fetch for it.
+func decodeTrieNodeChildren(data []byte) ([]types.Hash, error) {
+	var elems []rlp.RawValue
+	if err := rlp.DecodeBytes(data, &elems); err != nil {
+		return nil, fmt.Errorf("failed to decode trie node: %w", err)
+	}
+
+	switch len(elems) {
+	case 17:
+		var children []types.Hash
+		for _, slot := range elems[:16] {
+			if hash, ok := rawValueAsHash(slot); ok {
+				children = append(children, hash)
+			}
+		}
+		return children, nil
+	case 2:
+		if hash, ok := rawValueAsHash(elems[1]); ok {
+			return []types.Hash{hash}, nil
+		}
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unexpected trie node arity %d", len(elems))
+	}
+}
+
+// rawValueAsHash reports whether raw is a 32-byte string, i.e. a hash
+// reference to another node rather than that node embedded inline.
+func rawValueAsHash(raw rlp.RawValue) (types.Hash, bool) {
+	var b []byte
+	if err := rlp.DecodeBytes(raw, &b); err != nil || len(b) != 32 {
+		return types.Hash{}, false
+	}
+	return types.BytesToHash(b), true
+}
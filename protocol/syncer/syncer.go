@@ -0,0 +1,97 @@
+package syncer
+
+import (
+	"math/big"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+)
+
+// Syncer catches the local chain up to a peer, following whichever
+// SyncMode it was configured with.
+type Syncer struct {
+	logger hclog.Logger
+	db     *storage.KeyValueStorage
+	mode   SyncMode
+
+	fastSyncer *FastSyncer
+}
+
+// NewSyncer creates a syncer that runs in mode against db. pivotDepth is
+// only used in FastSync mode (0 uses DefaultPivotDepth).
+func NewSyncer(logger hclog.Logger, db *storage.KeyValueStorage, mode SyncMode, pivotDepth uint64) *Syncer {
+	return &Syncer{
+		logger:     logger.Named("syncer"),
+		db:         db,
+		mode:       mode,
+		fastSyncer: NewFastSyncer(logger, db, pivotDepth),
+	}
+}
+
+// Sync catches the local chain up to peer's bestHeader. In FastSync mode
+// it downloads headers/bodies/receipts for the whole chain and only the
+// pivot block's state trie, then continues as full sync from the pivot
+// onward; in FullSync mode it does the same header/body/receipt download
+// but starting from block 0, since this module does not yet execute
+// transactions to derive state itself.
+func (s *Syncer) Sync(peer Peer, bestHeader uint64) error {
+	if s.mode == FastSync {
+		pivot, err := s.fastSyncer.Sync(peer, bestHeader)
+		if err != nil {
+			return err
+		}
+
+		s.logger.Info("switching to full sync", "from", pivot, "to", bestHeader)
+		return s.fullSyncFrom(peer, pivot, bestHeader)
+	}
+
+	return s.fullSyncFrom(peer, 0, bestHeader)
+}
+
+// fullSyncFrom downloads and persists every header/body/receipt between
+// from and to, both inclusive - except from is treated as exclusive
+// whenever it is nonzero, since a nonzero from is always a block this
+// syncer (or a fast sync it continues) has already fetched. from == 0 is
+// the one case that means "nothing synced yet", so genesis itself still
+// gets downloaded and written like any other block.
+func (s *Syncer) fullSyncFrom(peer Peer, from, to uint64) error {
+	skeleton := NewSkeletonDownloader([]HeaderPeer{peer}, DefaultSkeletonChunkSize)
+
+	for from < to {
+		start := from
+		if start > 0 {
+			start++
+		}
+
+		chunkEnd := start + DefaultSkeletonChunkSize - 1
+		if chunkEnd > to {
+			chunkEnd = to
+		}
+
+		headers, err := skeleton.Download(start, chunkEnd)
+		if err != nil {
+			return err
+		}
+
+		if err := FetchBodiesAndReceipts(peer, s.db, headers); err != nil {
+			return err
+		}
+
+		for _, h := range headers {
+			parentDiff, ok := s.db.ReadDiff(h.ParentHash)
+			if !ok {
+				parentDiff = big.NewInt(0)
+			}
+			diff := new(big.Int).Add(parentDiff, new(big.Int).SetUint64(h.Difficulty))
+
+			if err := s.db.WriteCanonicalHeader(h, diff); err != nil {
+				return err
+			}
+		}
+
+		from = chunkEnd
+	}
+
+	return nil
+}
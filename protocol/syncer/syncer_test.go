@@ -0,0 +1,86 @@
+package syncer
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-sdk/blockchain/storage"
+	"github.com/0xPolygon/polygon-sdk/types"
+)
+
+// leafNode is a trivial 2-element (extension/leaf) trie node whose value
+// slot isn't a 32-byte hash, so decoding it yields no further children.
+func leafNode(t *testing.T) []byte {
+	t.Helper()
+	enc, err := rlp.EncodeToBytes([][]byte{{}, {}})
+	assert.NoError(t, err)
+	return enc
+}
+
+type fakePeer struct {
+	headers map[uint64]*types.Header
+	node    []byte
+}
+
+func (p *fakePeer) GetHeaders(number uint64, chunkSize uint64) ([]*types.Header, error) {
+	headers := make([]*types.Header, 0, chunkSize)
+	for i := uint64(0); i < chunkSize; i++ {
+		h, ok := p.headers[number+i]
+		if !ok {
+			break
+		}
+		headers = append(headers, h)
+	}
+	return headers, nil
+}
+
+func (p *fakePeer) GetBodies(hashes []types.Hash) ([]*types.Body, error) {
+	bodies := make([]*types.Body, len(hashes))
+	for i := range bodies {
+		bodies[i] = &types.Body{}
+	}
+	return bodies, nil
+}
+
+func (p *fakePeer) GetReceipts(hashes []types.Hash) ([][]*types.Receipt, error) {
+	return make([][]*types.Receipt, len(hashes)), nil
+}
+
+func (p *fakePeer) GetNodeData(hashes []types.Hash) ([][]byte, error) {
+	nodes := make([][]byte, len(hashes))
+	for i := range hashes {
+		nodes[i] = p.node
+	}
+	return nodes, nil
+}
+
+func TestSyncer_FullSync_WritesEveryHeader(t *testing.T) {
+	headers := buildLinkedHeaders(5)
+	peer := &fakePeer{headers: headers}
+	db := storage.NewKeyValueStorage(hclog.NewNullLogger(), newFakeKV()).(*storage.KeyValueStorage)
+
+	s := NewSyncer(hclog.NewNullLogger(), db, FullSync, 0)
+	assert.NoError(t, s.Sync(peer, 4))
+
+	for i := uint64(0); i <= 4; i++ {
+		_, ok := db.ReadHeader(headers[i].Hash)
+		assert.True(t, ok, "header %d should have been written", i)
+	}
+}
+
+func TestSyncer_FastSync_SwitchesToFullSyncAtPivot(t *testing.T) {
+	headers := buildLinkedHeaders(10)
+	peer := &fakePeer{headers: headers, node: leafNode(t)}
+	db := storage.NewKeyValueStorage(hclog.NewNullLogger(), newFakeKV()).(*storage.KeyValueStorage)
+
+	s := NewSyncer(hclog.NewNullLogger(), db, FastSync, 3)
+	assert.NoError(t, s.Sync(peer, 9))
+
+	for i := uint64(0); i <= 9; i++ {
+		_, ok := db.ReadHeader(headers[i].Hash)
+		assert.True(t, ok, "header %d should have been written", i)
+	}
+}
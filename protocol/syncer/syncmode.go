@@ -0,0 +1,40 @@
+package syncer
+
+import "fmt"
+
+// SyncMode selects how Syncer catches the local chain up to its peers
+type SyncMode int
+
+const (
+	// FullSync downloads headers, bodies and receipts for every block from
+	// genesis; Syncer executes no transactions in this mode
+	FullSync SyncMode = iota
+
+	// FastSync downloads headers, bodies and receipts for the full chain
+	// history but skips executing anything before a recent pivot block,
+	// downloading that pivot's state trie instead
+	FastSync
+)
+
+func (m SyncMode) String() string {
+	switch m {
+	case FullSync:
+		return "full"
+	case FastSync:
+		return "fast"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseSyncMode parses the --sync-mode CLI flag value
+func ParseSyncMode(s string) (SyncMode, error) {
+	switch s {
+	case "full", "":
+		return FullSync, nil
+	case "fast":
+		return FastSync, nil
+	default:
+		return FullSync, fmt.Errorf("unknown sync mode %q", s)
+	}
+}
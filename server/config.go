@@ -0,0 +1,30 @@
+package server
+
+import (
+	"flag"
+
+	"github.com/0xPolygon/polygon-sdk/protocol/syncer"
+)
+
+// Config holds the server's runtime settings as assembled from CLI flags
+type Config struct {
+	SyncMode syncer.SyncMode
+}
+
+// DefaultConfig returns a Config with the server's default settings
+func DefaultConfig() *Config {
+	return &Config{SyncMode: syncer.FullSync}
+}
+
+// RegisterFlags binds the server's CLI flags onto fs, writing parsed
+// values into cfg
+func RegisterFlags(fs *flag.FlagSet, cfg *Config) {
+	fs.Func("sync-mode", "sync mode, one of: full, fast (default: full)", func(s string) error {
+		mode, err := syncer.ParseSyncMode(s)
+		if err != nil {
+			return err
+		}
+		cfg.SyncMode = mode
+		return nil
+	})
+}
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xPolygon/polygon-sdk/protocol/syncer"
+)
+
+func TestRegisterFlags_SyncMode(t *testing.T) {
+	cfg := DefaultConfig()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	RegisterFlags(fs, cfg)
+
+	assert.NoError(t, fs.Parse([]string{"-sync-mode", "fast"}))
+	assert.Equal(t, syncer.FastSync, cfg.SyncMode)
+}
+
+func TestRegisterFlags_SyncModeRejectsUnknown(t *testing.T) {
+	cfg := DefaultConfig()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	RegisterFlags(fs, cfg)
+
+	assert.Error(t, fs.Parse([]string{"-sync-mode", "bogus"}))
+}
@@ -5,7 +5,7 @@ import (
 	"encoding/binary"
 	"fmt"
 
-	"github.com/0xPolygon/minimal/helper/hex"
+	"github.com/0xPolygon/polygon-sdk/helper/hex"
 	"github.com/ethereum/go-ethereum/rlp"
 	"golang.org/x/crypto/sha3"
 )
@@ -28,6 +28,10 @@ type Header struct {
 	MixHash      Hash     `json:"mixHash"`
 	Nonce        Nonce    `json:"nonce"`
 	Hash         Hash     `json:"hash"`
+
+	// RequestsHash commits to the block's execution-layer requests list
+	// (EIP-7685 style). It is nil for blocks built before the requests fork.
+	RequestsHash *Hash `json:"requestsHash,omitempty"`
 }
 
 func (h *Header) Equal(hh *Header) bool {
@@ -73,18 +77,25 @@ func (h *Header) Copy() *Header {
 
 	hh.ExtraData = make([]byte, len(h.ExtraData))
 	copy(hh.ExtraData[:], h.ExtraData[:])
+
+	if h.RequestsHash != nil {
+		requestsHash := *h.RequestsHash
+		hh.RequestsHash = &requestsHash
+	}
 	return hh
 }
 
 type Body struct {
 	Transactions []*Transaction
 	Uncles       []*Header
+	Requests     []Request
 }
 
 type Block struct {
 	Header       *Header
 	Transactions []*Transaction
 	Uncles       []*Header
+	Requests     []Request
 }
 
 func (b *Block) Hash() Hash {
@@ -103,6 +114,7 @@ func (b *Block) Body() *Body {
 	return &Body{
 		Transactions: b.Transactions,
 		Uncles:       b.Uncles,
+		Requests:     b.Requests,
 	}
 }
 
@@ -134,5 +146,6 @@ func (b *Block) WithSeal(header *Header) *Block {
 		Header:       &cpy,
 		Transactions: b.Transactions,
 		Uncles:       b.Uncles,
+		Requests:     b.Requests,
 	}
 }
@@ -0,0 +1,105 @@
+package types
+
+import (
+	"github.com/umbracle/fastrlp"
+)
+
+// MarshalRLPWith marshals the header into v, encoding RequestsHash as a
+// 17th element only when it is set so that headers from before the
+// requests fork keep their original (16-element) encoding and hash.
+func (h *Header) MarshalRLPWith(ar *fastrlp.Arena) *fastrlp.Value {
+	vv := ar.NewArray()
+
+	vv.Set(ar.NewCopyBytes(h.ParentHash.Bytes()))
+	vv.Set(ar.NewCopyBytes(h.Sha3Uncles.Bytes()))
+	vv.Set(ar.NewCopyBytes(h.Miner.Bytes()))
+	vv.Set(ar.NewCopyBytes(h.StateRoot.Bytes()))
+	vv.Set(ar.NewCopyBytes(h.TxRoot.Bytes()))
+	vv.Set(ar.NewCopyBytes(h.ReceiptsRoot.Bytes()))
+	vv.Set(ar.NewCopyBytes(h.LogsBloom[:]))
+	vv.Set(ar.NewUint(h.Difficulty))
+	vv.Set(ar.NewUint(h.Number))
+	vv.Set(ar.NewUint(h.GasLimit))
+	vv.Set(ar.NewUint(h.GasUsed))
+	vv.Set(ar.NewUint(h.Timestamp))
+	vv.Set(ar.NewCopyBytes(h.ExtraData))
+	vv.Set(ar.NewCopyBytes(h.MixHash.Bytes()))
+	vv.Set(ar.NewCopyBytes(h.Nonce[:]))
+	vv.Set(ar.NewCopyBytes(h.Hash.Bytes()))
+
+	if h.RequestsHash != nil {
+		vv.Set(ar.NewCopyBytes(h.RequestsHash[:]))
+	}
+
+	return vv
+}
+
+// UnmarshalRLPFrom unmarshals the header from v. A 17th element is read
+// back into RequestsHash; its absence leaves RequestsHash nil, mirroring
+// MarshalRLPWith's fork gate.
+func (h *Header) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+
+	if err := elems[0].GetHash(h.ParentHash[:]); err != nil {
+		return err
+	}
+	if err := elems[1].GetHash(h.Sha3Uncles[:]); err != nil {
+		return err
+	}
+	if err := elems[2].GetAddr(h.Miner[:]); err != nil {
+		return err
+	}
+	if err := elems[3].GetHash(h.StateRoot[:]); err != nil {
+		return err
+	}
+	if err := elems[4].GetHash(h.TxRoot[:]); err != nil {
+		return err
+	}
+	if err := elems[5].GetHash(h.ReceiptsRoot[:]); err != nil {
+		return err
+	}
+	if _, err := elems[6].GetBytes(h.LogsBloom[:0]); err != nil {
+		return err
+	}
+	if h.Difficulty, err = elems[7].GetUint64(); err != nil {
+		return err
+	}
+	if h.Number, err = elems[8].GetUint64(); err != nil {
+		return err
+	}
+	if h.GasLimit, err = elems[9].GetUint64(); err != nil {
+		return err
+	}
+	if h.GasUsed, err = elems[10].GetUint64(); err != nil {
+		return err
+	}
+	if h.Timestamp, err = elems[11].GetUint64(); err != nil {
+		return err
+	}
+	if h.ExtraData, err = elems[12].GetBytes(nil); err != nil {
+		return err
+	}
+	if err := elems[13].GetHash(h.MixHash[:]); err != nil {
+		return err
+	}
+	if _, err := elems[14].GetBytes(h.Nonce[:0]); err != nil {
+		return err
+	}
+	if err := elems[15].GetHash(h.Hash[:]); err != nil {
+		return err
+	}
+
+	h.RequestsHash = nil
+	if len(elems) > 16 {
+		var requestsHash Hash
+		if err := elems[16].GetHash(requestsHash[:]); err != nil {
+			return err
+		}
+		h.RequestsHash = &requestsHash
+	}
+
+	return nil
+}
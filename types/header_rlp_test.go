@@ -0,0 +1,44 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/fastrlp"
+)
+
+func roundTrip(t *testing.T, h *Header) *Header {
+	t.Helper()
+
+	ar := &fastrlp.Arena{}
+	data := h.MarshalRLPWith(ar).MarshalTo(nil)
+
+	p := &fastrlp.Parser{}
+	v, err := p.Parse(data)
+	assert.NoError(t, err)
+
+	got := &Header{}
+	assert.NoError(t, got.UnmarshalRLPFrom(p, v))
+	return got
+}
+
+func TestHeader_RLP_RoundTrip_NoRequestsFork(t *testing.T) {
+	h := &Header{Number: 5, Difficulty: 10, GasLimit: 100}
+	h.Hash[0] = 0xab
+
+	got := roundTrip(t, h)
+	assert.Equal(t, h, got)
+	assert.Nil(t, got.RequestsHash)
+}
+
+func TestHeader_RLP_RoundTrip_WithRequestsHash(t *testing.T) {
+	var requestsHash Hash
+	requestsHash[0] = 0xcd
+
+	h := &Header{Number: 7, RequestsHash: &requestsHash}
+	h.Hash[0] = 0xef
+
+	got := roundTrip(t, h)
+	assert.Equal(t, h, got)
+	assert.Equal(t, requestsHash, *got.RequestsHash)
+}
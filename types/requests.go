@@ -0,0 +1,105 @@
+package types
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"golang.org/x/crypto/sha3"
+)
+
+// Request is a single typed execution-layer-originated request committed to
+// via the header's RequestsHash (EIP-7685 style). Each request type defines
+// its own RLP payload; the flat encoding used everywhere a Request is
+// stored or hashed is type_byte || rlp(payload).
+type Request interface {
+	Type() byte
+}
+
+// request type bytes
+const (
+	// RequestTypeValidatorDeposit is the type byte for ValidatorDeposit
+	RequestTypeValidatorDeposit byte = 0x00
+)
+
+// ValidatorDeposit lets a consensus engine (IBFT in particular) surface a
+// validator-set change through the generic requests list instead of an
+// ad-hoc extraData schema.
+type ValidatorDeposit struct {
+	Validator Address
+	Amount    uint64
+}
+
+// Type implements Request
+func (d *ValidatorDeposit) Type() byte {
+	return RequestTypeValidatorDeposit
+}
+
+// MarshalRequest encodes req as type_byte || rlp(payload)
+func MarshalRequest(req Request) ([]byte, error) {
+	payload, err := rlp.EncodeToBytes(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte{req.Type()}, payload...), nil
+}
+
+// UnmarshalRequest decodes a flat-encoded request, dispatching on its
+// leading type byte
+func UnmarshalRequest(data []byte) (Request, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty request")
+	}
+
+	switch data[0] {
+	case RequestTypeValidatorDeposit:
+		req := &ValidatorDeposit{}
+		if err := rlp.DecodeBytes(data[1:], req); err != nil {
+			return nil, err
+		}
+		return req, nil
+	default:
+		return nil, fmt.Errorf("unknown request type %#x", data[0])
+	}
+}
+
+// CalcRequestsHash hashes reqs the way EIP-7685 commits to them: one
+// SHA-256 digest per request type over the concatenation of that type's
+// flat-encoded requests, then keccak256 over the concatenation of those
+// per-type digests in ascending type order.
+func CalcRequestsHash(reqs []Request) (Hash, error) {
+	byType := map[byte][][]byte{}
+	for _, req := range reqs {
+		enc, err := MarshalRequest(req)
+		if err != nil {
+			return Hash{}, err
+		}
+		byType[req.Type()] = append(byType[req.Type()], enc)
+	}
+
+	reqTypes := make([]byte, 0, len(byType))
+	for t := range byType {
+		reqTypes = append(reqTypes, t)
+	}
+	sort.Slice(reqTypes, func(i, j int) bool { return reqTypes[i] < reqTypes[j] })
+
+	var digests []byte
+	for _, t := range reqTypes {
+		h := sha256.New()
+		for _, enc := range byType[t] {
+			h.Write(enc)
+		}
+		digests = append(digests, h.Sum(nil)...)
+	}
+
+	return keccak256(digests), nil
+}
+
+func keccak256(data []byte) (h Hash) {
+	hw := sha3.NewLegacyKeccak256()
+	hw.Write(data)
+	hw.Sum(h[:0])
+	return h
+}
@@ -0,0 +1,40 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatorDeposit_MarshalRoundtrip(t *testing.T) {
+	req := &ValidatorDeposit{Validator: StringToAddress("1"), Amount: 100}
+
+	enc, err := MarshalRequest(req)
+	assert.NoError(t, err)
+	assert.Equal(t, RequestTypeValidatorDeposit, enc[0])
+
+	decoded, err := UnmarshalRequest(enc)
+	assert.NoError(t, err)
+	assert.Equal(t, req, decoded)
+}
+
+func TestUnmarshalRequest_UnknownType(t *testing.T) {
+	_, err := UnmarshalRequest([]byte{0xff})
+	assert.Error(t, err)
+}
+
+func TestCalcRequestsHash_DeterministicAndDistinctFromEmpty(t *testing.T) {
+	a := &ValidatorDeposit{Validator: StringToAddress("1"), Amount: 100}
+	b := &ValidatorDeposit{Validator: StringToAddress("2"), Amount: 200}
+
+	h1, err := CalcRequestsHash([]Request{a, b})
+	assert.NoError(t, err)
+
+	h2, err := CalcRequestsHash([]Request{a, b})
+	assert.NoError(t, err)
+	assert.Equal(t, h1, h2)
+
+	h3, err := CalcRequestsHash([]Request{})
+	assert.NoError(t, err)
+	assert.NotEqual(t, h1, h3)
+}